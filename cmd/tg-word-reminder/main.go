@@ -16,7 +16,10 @@ import (
 var logger = slog.Default()
 
 func main() {
-	config.LoadConfig("config.json")
+	if err := config.LoadConfig("config.json"); err != nil {
+		logger.Error("failed to load config", "error", err)
+		os.Exit(1)
+	}
 	if err := db.InitDB(config.AppConfig.Database); err != nil {
 		logger.Error("failed to initialize database", "error", err)
 		os.Exit(1)
@@ -27,6 +30,7 @@ func main() {
 
 	opts := []bot.Option{
 		bot.WithDefaultHandler(reminderBot.DefaultHandler),
+		bot.WithMiddlewares(reminderBot.DedupMiddleware()),
 	}
 	b, err := bot.New(config.AppConfig.Telegram.Token, opts...)
 	if err != nil {
@@ -34,11 +38,10 @@ func main() {
 		os.Exit(1)
 	}
 
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/start", bot.MatchTypeExact, reminderBot.HandleStart)
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/clear", bot.MatchTypeExact, reminderBot.HandleClear)
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/setnum", bot.MatchTypePrefix, reminderBot.HandleSetNumOfPairs)
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/setfreq", bot.MatchTypePrefix, reminderBot.HandleSetFrequency)
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/getpair", bot.MatchTypeExact, reminderBot.HandleGetPair)
+	for name, handler := range reminderBot.CommandHandlers {
+		b.RegisterHandlerMatchFunc(reminderBot.MatchCommand(name), handler, reminderBot.NormalizeCommand)
+	}
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "setnum:", bot.MatchTypePrefix, reminderBot.HandleSetNumCallback)
 
 	go reminderBot.StartPeriodicMessages(ctx, b)
 
@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/logger"
+)
+
+// seenUpdatesLimit is how many recent update IDs are remembered for deduplication
+const seenUpdatesLimit = 1000
+
+// updateDeduplicator remembers the most recent update IDs in a fixed-size ring
+type updateDeduplicator struct {
+	mu   sync.Mutex
+	seen map[int64]struct{}
+	ring []int64
+	next int
+}
+
+func newUpdateDeduplicator(limit int) *updateDeduplicator {
+	return &updateDeduplicator{
+		seen: make(map[int64]struct{}, limit),
+		ring: make([]int64, 0, limit),
+	}
+}
+
+// firstSeen records id and reports whether it had not been seen before
+func (d *updateDeduplicator) firstSeen(id int64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.seen[id]; ok {
+		return false
+	}
+	if len(d.ring) < cap(d.ring) {
+		d.ring = append(d.ring, id)
+	} else {
+		delete(d.seen, d.ring[d.next]) // Forget the oldest ID to keep the set bounded
+		d.ring[d.next] = id
+		d.next = (d.next + 1) % len(d.ring)
+	}
+	d.seen[id] = struct{}{}
+	return true
+}
+
+// DedupMiddleware drops updates Telegram delivers more than once, so a command is not applied twice
+func DedupMiddleware() bot.Middleware {
+	dedup := newUpdateDeduplicator(seenUpdatesLimit)
+	return func(next bot.HandlerFunc) bot.HandlerFunc {
+		return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+			if update != nil && !dedup.firstSeen(update.ID) {
+				logger.Debug("ignoring duplicate update", "update_id", update.ID)
+				return
+			}
+			next(ctx, b, update)
+		}
+	}
+}
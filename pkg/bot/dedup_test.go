@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func TestDedupMiddlewareDropsRepeatedUpdate(t *testing.T) {
+	calls := 0
+	handler := DedupMiddleware()(func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		calls++
+	})
+
+	update := &models.Update{ID: 42}
+	handler(context.Background(), nil, update)
+	handler(context.Background(), nil, update)
+	if calls != 1 {
+		t.Errorf("handler called %d times for a repeated update, want 1", calls)
+	}
+
+	handler(context.Background(), nil, &models.Update{ID: 43})
+	if calls != 2 {
+		t.Errorf("handler called %d times after a new update, want 2", calls)
+	}
+}
+
+func TestUpdateDeduplicatorForgetsOldest(t *testing.T) {
+	const limit = 3
+	d := newUpdateDeduplicator(limit)
+
+	if !d.firstSeen(1) {
+		t.Fatal("first update reported as seen")
+	}
+	for id := int64(2); id <= limit+1; id++ {
+		if !d.firstSeen(id) {
+			t.Fatalf("update %d reported as seen", id)
+		}
+	}
+	// 1 was pushed out by limit newer IDs, while the newer ones are still remembered
+	if !d.firstSeen(1) {
+		t.Error("update 1 still remembered after the ring wrapped")
+	}
+	if d.firstSeen(limit + 1) {
+		t.Errorf("update %d forgotten too early", limit+1)
+	}
+}
@@ -0,0 +1,43 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/logger"
+)
+
+// newErrorRef returns a short random reference that ties a user-facing error to its log entry
+func newErrorRef() string {
+	buf := make([]byte, 3)
+	if _, err := rand.Read(buf); err != nil {
+		return "000000"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// logErrorRef logs an unexpected error under a fresh reference and returns the reference,
+// for replies that cannot go through internalError, e.g. callback query answers
+func logErrorRef(logMsg string, err error, args ...any) string {
+	ref := newErrorRef()
+	logger.Error(logMsg, append([]any{"error", err, "ref", ref}, args...)...)
+	return ref
+}
+
+// internalError logs an unexpected error under a fresh reference and replies with text followed by that reference,
+// so operators can find the log entry from what the user reports
+func internalError(ctx context.Context, b *bot.Bot, msg *models.Message, text, logMsg string, err error) {
+	args := []any{"chat_id", msg.Chat.ID}
+	if msg.From != nil {
+		args = append(args, "user_id", msg.From.ID)
+	}
+	ref := logErrorRef(logMsg, err, args...)
+
+	if _, sendErr := reply(ctx, b, msg, fmt.Sprintf("%s\n\nError ref: %s", text, ref)); sendErr != nil {
+		logger.Error("failed to send error message", "ref", ref, "error", sendErr)
+	}
+}
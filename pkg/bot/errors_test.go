@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/smith3v/tg-word-reminder/pkg/logger"
+)
+
+// captureLog redirects the logger to a buffer for the duration of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := logger.Logger
+	logger.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+	t.Cleanup(func() { logger.Logger = previous })
+	return &buf
+}
+
+func TestInternalErrorSharesRefBetweenReplyAndLog(t *testing.T) {
+	logs := captureLog(t)
+	b, fake := newTestBot(t)
+
+	internalError(context.Background(), b, testMessage("/clear"), "Failed to clear your word pairs.", "failed to clear word pairs", errors.New("connection reset"))
+
+	sent := fake.sent()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(sent))
+	}
+	_, ref, found := strings.Cut(sent[0], "Error ref: ")
+	if !found || len(ref) != 6 {
+		t.Fatalf("reply %q carries no error ref", sent[0])
+	}
+	if !strings.HasPrefix(sent[0], "Failed to clear your word pairs.") {
+		t.Errorf("reply %q lost the error text", sent[0])
+	}
+
+	line := logs.String()
+	for _, want := range []string{`msg="failed to clear word pairs"`, "ref=" + ref, `error="connection reset"`, "user_id=1"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log %q does not contain %s", line, want)
+		}
+	}
+}
+
+func TestLogErrorRef(t *testing.T) {
+	logs := captureLog(t)
+
+	ref := logErrorRef("failed to update user settings", errors.New("timeout"), "user_id", 7)
+	if len(ref) != 6 {
+		t.Fatalf("ref %q is not 6 characters long", ref)
+	}
+	if line := logs.String(); !strings.Contains(line, "ref="+ref) || !strings.Contains(line, "user_id=7") {
+		t.Errorf("log %q does not carry ref %s and the extra fields", line, ref)
+	}
+}
@@ -3,11 +3,12 @@ package bot
 
 import (
 	"context"
-	"encoding/csv"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
@@ -17,7 +18,19 @@ import (
 	"gorm.io/gorm"
 )
 
+// maxVacationDays is the longest pause /vacation accepts
+const maxVacationDays = 365
+
+// maxRemindersPerDay is the most reminders /setfreq accepts, one per minute
+const maxRemindersPerDay = 24 * 60
+
 func DefaultHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	// Inline queries have no dedicated handler type, so they arrive here
+	if update != nil && update.InlineQuery != nil {
+		HandleInlineQuery(ctx, b, update)
+		return
+	}
+
 	if update == nil || update.Message == nil {
 		logger.Error("received invalid update in defaultHandler")
 		return
@@ -31,10 +44,14 @@ func DefaultHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 	// Check if the message contains a document (file)
 	if update.Message.Document == nil {
-		_, err := b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Say /getpair, /setnum, /setfreq, or /clear to use the bot. If you attach a CSV file, I'll upload the word pairs to your account.",
-		})
+		text := "Say /help to see the available commands. If you attach a CSV file, I'll upload the word pairs to your account."
+		if update.Message.Text == "" {
+			// Stickers, photos, voice messages and the like carry no text
+			text = "I didn't understand that kind of message. Send /help to see what I can do."
+		} else if command, ok := suggestCommand(update.Message.Text); ok {
+			text = fmt.Sprintf("Did you mean /%s? Say /help to see all commands.", command)
+		}
+		_, err := reply(ctx, b, update.Message, text)
 		if err != nil {
 			logger.Error("failed to send message in defaultHandler", "error", err)
 		}
@@ -45,21 +62,14 @@ func DefaultHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 	// Check if the file is a CSV
 	if !strings.HasSuffix(update.Message.Document.FileName, ".csv") {
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "The uploaded file is not a CSV. Please upload a valid CSV file.",
-		})
+		reply(ctx, b, update.Message, "The uploaded file is not a CSV. Please upload a valid CSV file.")
 		return
 	}
 
 	// Download the file
 	file, err := b.GetFile(ctx, &bot.GetFileParams{FileID: update.Message.Document.FileID})
 	if err != nil {
-		logger.Error("failed to get file", "error", err)
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Failed to download the file. Please try again.",
-		})
+		internalError(ctx, b, update.Message, "Failed to download the file. Please try again.", "failed to get file", err)
 		return
 	}
 
@@ -69,55 +79,19 @@ func DefaultHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	// Open the file
 	resp, err := http.Get(fileURL)
 	if err != nil {
-		logger.Error("failed to open file", "error", err)
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Failed to open the file. Please try again.",
-		})
+		internalError(ctx, b, update.Message, "Failed to open the file. Please try again.", "failed to open file", err)
 		return
 	}
 	defer resp.Body.Close()
 
 	// Read the CSV file
-	reader := csv.NewReader(resp.Body)
-	reader.Comma = '\t' // Set the delimiter to tab
-	records, err := reader.ReadAll()
+	records, comma, err := readVocabulary(resp.Body, importSeparatorSetting(update.Message.From.ID))
 	if err != nil {
-		logger.Error("failed to read CSV file", "error", err)
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Failed to read the CSV file. Please ensure it is in the correct format.",
-		})
-		return
-	}
-
-	// Process each record
-	for _, record := range records {
-		if len(record) != 2 {
-			b.SendMessage(ctx, &bot.SendMessageParams{
-				ChatID: update.Message.Chat.ID,
-				Text:   fmt.Sprintf("Invalid format in record: %v. Please use 'word1\tword2' format.", record),
-			})
-			continue
-		}
-		wordPair := db.WordPair{
-			UserID: update.Message.From.ID,
-			Word1:  strings.TrimSpace(record[0]),
-			Word2:  strings.TrimSpace(record[1]),
-		}
-		if err := db.DB.Create(&wordPair).Error; err != nil {
-			logger.Error("failed to create word pair", "user_id", update.Message.From.ID, "error", err)
-			b.SendMessage(ctx, &bot.SendMessageParams{
-				ChatID: update.Message.Chat.ID,
-				Text:   fmt.Sprintf("Failed to upload word pair: %v", record),
-			})
-		}
+		internalError(ctx, b, update.Message, "Failed to read the CSV file. Please ensure it is in the correct format.", "failed to read CSV file", err)
+		return
 	}
 
-	b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID: update.Message.Chat.ID,
-		Text:   "Word pairs uploaded successfully.",
-	})
+	importRecords(ctx, b, update.Message, records, fmt.Sprintf("word1%cword2", comma))
 }
 
 func HandleStart(ctx context.Context, b *bot.Bot, update *models.Update) {
@@ -128,36 +102,27 @@ func HandleStart(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 	// Check if user settings already exist
 	var settings db.UserSettings
-	if err := db.DB.Where("user_id = ?", update.Message.From.ID).First(&settings).Error; err != nil {
+	if err := settingsQuery(update.Message.From.ID).First(&settings).Error; err != nil {
 		if err == gorm.ErrRecordNotFound { // User settings do not exist
 			settings = db.UserSettings{
 				UserID:          update.Message.From.ID,
+				BotID:           config.AppConfig.Telegram.BotID,
 				PairsToSend:     1, // Default value
 				RemindersPerDay: 1, // Default value
 			}
 			if err := db.DB.Create(&settings).Error; err != nil {
-				logger.Error("failed to create user settings", "user_id", update.Message.From.ID, "error", err)
-				b.SendMessage(ctx, &bot.SendMessageParams{
-					ChatID: update.Message.Chat.ID,
-					Text:   "Failed to create your settings. Please try again later.",
-				})
+				internalError(ctx, b, update.Message, "Failed to create your settings. Please try again later.", "failed to create user settings", err)
 				return
 			}
 		} else {
-			logger.Error("failed to check user settings", "error", err)
-			b.SendMessage(ctx, &bot.SendMessageParams{
-				ChatID: update.Message.Chat.ID,
-				Text:   "An error occurred while checking your settings. Please try again later.",
-			})
+			internalError(ctx, b, update.Message, "An error occurred while checking your settings. Please try again later.", "failed to check user settings", err)
 			return
 		}
 	}
 
-	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:    update.Message.Chat.ID,
-		Text:      "Welcome\\!\n\nThis bot helps to learn the word pairs or idioms\\, for instance\\, when you learn a language\\. It sends the messages to you with random idioms a few times a day\\. You can choose how often \\(`/setfreq n`\\) and how many \\(`/setnum m`\\) idioms to send every time\\.\n\nYou have to upload your vocabulary first\\. You can send a CSV file here with the word pairs separated by tabs\\. Please refer to [the example](https://raw.githubusercontent.com/smith3v/tg-word-reminder/refs/heads/main/example.csv) for a file format\\, or to [Dutch\\-English vocabulary](https://raw.githubusercontent.com/smith3v/tg-word-reminder/refs/heads/main/dutch-english.csv)\\. ",
-		ParseMode: models.ParseModeMarkdown,
-	})
+	params := replyParams(update.Message, "Welcome\\!\n\nThis bot helps to learn the word pairs or idioms\\, for instance\\, when you learn a language\\. It sends the messages to you with random idioms a few times a day\\. You can choose how often \\(`/setfreq n`\\) and how many \\(`/setnum m`\\) idioms to send every time\\.\n\nYou have to upload your vocabulary first\\. You can send a CSV file here with the word pairs separated by tabs\\, commas or semicolons\\. Please refer to [the example](https://raw.githubusercontent.com/smith3v/tg-word-reminder/refs/heads/main/example.csv) for a file format\\, or to [Dutch\\-English vocabulary](https://raw.githubusercontent.com/smith3v/tg-word-reminder/refs/heads/main/dutch-english.csv)\\. ")
+	params.ParseMode = models.ParseModeMarkdown
+	_, err := b.SendMessage(ctx, params)
 	if err != nil {
 		logger.Error("failed to send welcome message", "user_id", update.Message.From.ID, "error", err)
 	}
@@ -169,11 +134,40 @@ func HandleClear(ctx context.Context, b *bot.Bot, update *models.Update) {
 		return
 	}
 
-	db.DB.Where("user_id = ?", update.Message.From.ID).Delete(&db.WordPair{})
-	b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID: update.Message.Chat.ID,
-		Text:   "Your word pair list has been cleared.",
+	if err := db.DB.Where("user_id = ?", update.Message.From.ID).Delete(&db.WordPair{}).Error; err != nil {
+		internalError(ctx, b, update.Message, "Failed to clear your word pairs. Please try again later.", "failed to clear word pairs", err)
+		return
+	}
+	reply(ctx, b, update.Message, "Your word pair list has been cleared.")
+}
+
+func HandleSwap(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleSwap")
+		return
+	}
+
+	// Postgres evaluates the right-hand sides against the old row, so this swaps the columns in place.
+	// Pending words without a translation are left alone, as swapping would leave them with an empty first word.
+	result := db.DB.Model(&db.WordPair{}).Where("user_id = ? AND word2 <> ''", update.Message.From.ID).Updates(map[string]interface{}{
+		"word1": gorm.Expr("word2"),
+		"word2": gorm.Expr("word1"),
 	})
+	if result.Error != nil {
+		internalError(ctx, b, update.Message, "Failed to swap your word pairs. Please try again later.", "failed to swap word pairs", result.Error)
+		return
+	}
+
+	var pending int64
+	if err := db.DB.Model(&db.WordPair{}).Where("user_id = ? AND word2 = ''", update.Message.From.ID).Count(&pending).Error; err != nil {
+		logger.Error("failed to count pending words", "user_id", update.Message.From.ID, "error", err)
+	}
+
+	text := fmt.Sprintf("Swapped the words in %d pairs. The first and second words of every pair are now exchanged.", result.RowsAffected)
+	if pending > 0 {
+		text += fmt.Sprintf("\n\n%d words without a translation were left untouched. Say /fill to see them.", pending)
+	}
+	reply(ctx, b, update.Message, text)
 }
 
 func HandleSetNumOfPairs(ctx context.Context, b *bot.Bot, update *models.Update) {
@@ -184,36 +178,69 @@ func HandleSetNumOfPairs(ctx context.Context, b *bot.Bot, update *models.Update)
 
 	parts := strings.Fields(update.Message.Text)
 	if len(parts) != 2 {
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Please use the format: /setnum <number>\n\nTo set the number of pairs in each reminder.",
-		})
+		params := replyParams(update.Message, "Please use the format: /setnum <number>\n\nTo set the number of pairs in each reminder, or pick one of the presets below.")
+		params.ReplyMarkup = pairsPresetKeyboard()
+		b.SendMessage(ctx, params)
 		return
 	}
 
 	pairsCount, err := strconv.Atoi(parts[1])
-	if err != nil || pairsCount <= 0 {
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Please provide a valid number of pairs in each reminder.",
-		})
+	if err != nil || pairsCount <= 0 || pairsCount > MaxPairsPerReminder {
+		reply(ctx, b, update.Message, fmt.Sprintf("Please provide a valid number of pairs in each reminder (1-%d).", MaxPairsPerReminder))
 		return
 	}
 
-	settings := db.UserSettings{UserID: update.Message.From.ID, PairsToSend: pairsCount}
-	if err := db.DB.Where("user_id = ?", update.Message.From.ID).Assign(settings).FirstOrCreate(&settings).Error; err != nil {
-		logger.Error("failed to update user settings", "error", err)
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Failed to update settings. Please try again.",
-		})
+	if err := setPairsToSend(update.Message.From.ID, pairsCount); err != nil {
+		internalError(ctx, b, update.Message, "Failed to update settings. Please try again.", "failed to update user settings", err)
 		return
 	}
 
-	b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID: update.Message.Chat.ID,
-		Text:   fmt.Sprintf("Number of pairs in each reminder has been set to %d.", pairsCount),
-	})
+	reply(ctx, b, update.Message, fmt.Sprintf("Number of pairs in each reminder has been set to %d.", pairsCount))
+}
+
+// HandleSetNumCallback applies a pairs count preset picked from the /setnum keyboard
+func HandleSetNumCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.CallbackQuery == nil {
+		logger.Error("invalid update in HandleSetNumCallback")
+		return
+	}
+	query := update.CallbackQuery
+
+	answer := "Failed to update settings. Please try again."
+	pairsCount, err := strconv.Atoi(strings.TrimPrefix(query.Data, pairsPresetCallbackPrefix))
+	if err != nil || pairsCount <= 0 || pairsCount > MaxPairsPerReminder {
+		logger.Error("invalid pairs preset callback", "data", query.Data, "user_id", query.From.ID)
+		answer = "Unknown preset."
+	} else if err := setPairsToSend(query.From.ID, pairsCount); err != nil {
+		answer = fmt.Sprintf("%s Error ref: %s", answer, logErrorRef("failed to update user settings", err, "user_id", query.From.ID))
+	} else {
+		answer = fmt.Sprintf("Number of pairs in each reminder has been set to %d.", pairsCount)
+	}
+
+	if _, err := b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+		Text:            answer,
+	}); err != nil {
+		logger.Error("failed to answer callback query", "user_id", query.From.ID, "error", err)
+	}
+}
+
+// settingsQuery selects the settings of userID kept by this bot instance
+func settingsQuery(userID int64) *gorm.DB {
+	return db.DB.Where("user_id = ? AND bot_id = ?", userID, config.AppConfig.Telegram.BotID)
+}
+
+// updateSettings writes the given columns of the user's settings, creating the settings if needed.
+// Unlike assigning a struct, a map also writes zero values such as false or nil.
+func updateSettings(userID int64, values map[string]interface{}) error {
+	var settings db.UserSettings
+	return settingsQuery(userID).Assign(values).FirstOrCreate(&settings, db.UserSettings{UserID: userID, BotID: config.AppConfig.Telegram.BotID}).Error
+}
+
+// setPairsToSend stores the number of pairs sent in each reminder, creating the settings if needed
+func setPairsToSend(userID int64, pairsCount int) error {
+	settings := db.UserSettings{UserID: userID, BotID: config.AppConfig.Telegram.BotID, PairsToSend: pairsCount}
+	return settingsQuery(userID).Assign(settings).FirstOrCreate(&settings).Error
 }
 
 func HandleSetFrequency(ctx context.Context, b *bot.Bot, update *models.Update) {
@@ -224,36 +251,264 @@ func HandleSetFrequency(ctx context.Context, b *bot.Bot, update *models.Update)
 
 	parts := strings.Fields(update.Message.Text)
 	if len(parts) != 2 {
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Please use the format: /setfreq <number>\n\nTo set the frequency of reminders per day.",
-		})
+		reply(ctx, b, update.Message, "Please use the format: /setfreq <number>\n\nTo set the frequency of reminders per day.")
 		return
 	}
 
 	frequency, err := strconv.Atoi(parts[1])
-	if err != nil || frequency <= 0 {
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Please provide a valid number of reminders per day.",
-		})
+	if err != nil || frequency <= 0 || frequency > maxRemindersPerDay {
+		reply(ctx, b, update.Message, fmt.Sprintf("Please provide a valid number of reminders per day (1-%d).", maxRemindersPerDay))
 		return
 	}
 
-	settings := db.UserSettings{UserID: update.Message.From.ID, RemindersPerDay: frequency}
-	if err := db.DB.Where("user_id = ?", update.Message.From.ID).Assign(settings).FirstOrCreate(&settings).Error; err != nil {
-		logger.Error("failed to update user settings", "error", err)
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Failed to update settings. Please try again.",
-		})
+	settings := db.UserSettings{UserID: update.Message.From.ID, BotID: config.AppConfig.Telegram.BotID, RemindersPerDay: frequency}
+	if err := settingsQuery(update.Message.From.ID).Assign(settings).FirstOrCreate(&settings).Error; err != nil {
+		internalError(ctx, b, update.Message, "Failed to update settings. Please try again.", "failed to update user settings", err)
 		return
 	}
 
-	b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID: update.Message.Chat.ID,
-		Text:   fmt.Sprintf("Frequency of reminders has been set to %d per day.", frequency),
-	})
+	reply(ctx, b, update.Message, fmt.Sprintf("Frequency of reminders has been set to %d per day.", frequency))
+}
+
+func HandleWeekdays(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleWeekdays")
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 || (parts[1] != "on" && parts[1] != "off") {
+		reply(ctx, b, update.Message, "Please use the format: /weekdays on|off\n\nTo skip reminders on Saturdays and Sundays.")
+		return
+	}
+	weekdaysOnly := parts[1] == "on"
+
+	if err := updateSettings(update.Message.From.ID, map[string]interface{}{"weekdays_only": weekdaysOnly}); err != nil {
+		internalError(ctx, b, update.Message, "Failed to update settings. Please try again.", "failed to update user settings", err)
+		return
+	}
+
+	text := "Reminders will be sent every day of the week."
+	if weekdaysOnly {
+		text = "Reminders will be sent on weekdays only."
+	}
+	reply(ctx, b, update.Message, text)
+}
+
+func HandleVacation(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleVacation")
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		reply(ctx, b, update.Message, "Please use the format: /vacation <days>\n\nTo pause reminders for a number of days. Use /vacation 0 to resume them now.")
+		return
+	}
+
+	days, err := strconv.Atoi(parts[1])
+	if err != nil || days < 0 || days > maxVacationDays {
+		reply(ctx, b, update.Message, fmt.Sprintf("Please provide a valid number of days (0-%d).", maxVacationDays))
+		return
+	}
+
+	var resumeAt *time.Time
+	text := "Welcome back! Reminders are resumed."
+	if days > 0 {
+		t := time.Now().AddDate(0, 0, days)
+		resumeAt = &t
+		text = fmt.Sprintf("Enjoy your vacation! Reminders are paused until %s.", t.Format("2006-01-02 15:04"))
+	}
+
+	if err := updateSettings(update.Message.From.ID, map[string]interface{}{"resume_at": resumeAt}); err != nil {
+		internalError(ctx, b, update.Message, "Failed to update settings. Please try again.", "failed to update user settings", err)
+		return
+	}
+
+	reply(ctx, b, update.Message, text)
+}
+
+func HandleWordOfTheDay(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleWordOfTheDay")
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 || (parts[1] != "on" && parts[1] != "off") {
+		reply(ctx, b, update.Message, "Please use the format: /wotd on|off\n\nTo get a word of the day every morning.")
+		return
+	}
+	enabled := parts[1] == "on"
+
+	if err := updateSettings(update.Message.From.ID, map[string]interface{}{"word_of_the_day": enabled}); err != nil {
+		internalError(ctx, b, update.Message, "Failed to update settings. Please try again.", "failed to update user settings", err)
+		return
+	}
+
+	text := "Word of the day is turned off."
+	if enabled {
+		text = fmt.Sprintf("You will get a word of the day every day after %02d:00.", config.AppConfig.Reminders.WordOfTheDayHour)
+	}
+	reply(ctx, b, update.Message, text)
+}
+
+func HandleHide(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleHide")
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 || (parts[1] != HideFirst && parts[1] != HideSecond && parts[1] != HideRandom && parts[1] != HideNone) {
+		reply(ctx, b, update.Message, "Please use the format: /hide first|second|random|none\n\nTo choose which word of a pair is hidden under the spoiler, or to show both.")
+		return
+	}
+
+	if err := updateSettings(update.Message.From.ID, map[string]interface{}{"hidden_word": parts[1]}); err != nil {
+		internalError(ctx, b, update.Message, "Failed to update settings. Please try again.", "failed to update user settings", err)
+		return
+	}
+
+	text := fmt.Sprintf("The %s word of each pair will be hidden.", parts[1])
+	switch parts[1] {
+	case HideRandom:
+		text = "A random word of each pair will be hidden."
+	case HideNone:
+		text = "Both words of each pair will be shown without a spoiler."
+	}
+	reply(ctx, b, update.Message, text)
+}
+
+func HandleLabels(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleLabels")
+		return
+	}
+
+	parts := strings.Fields(update.Message.Text)
+	var label1, label2 string
+	switch {
+	case len(parts) == 2 && parts[1] == "off":
+		// Both labels stay empty
+	case len(parts) == 3 && utf8.RuneCountInString(parts[1]) <= maxLabelLength && utf8.RuneCountInString(parts[2]) <= maxLabelLength:
+		label1, label2 = parts[1], parts[2]
+	default:
+		reply(ctx, b, update.Message, fmt.Sprintf("Please use the format: /labels <label1> <label2>\n\nTo show labels such as Dutch and English before the words, up to %d characters each. Use /labels off to remove them.", maxLabelLength))
+		return
+	}
+
+	if err := updateSettings(update.Message.From.ID, map[string]interface{}{"word_label1": label1, "word_label2": label2}); err != nil {
+		internalError(ctx, b, update.Message, "Failed to update settings. Please try again.", "failed to update user settings", err)
+		return
+	}
+
+	text := "Labels removed."
+	if label1 != "" {
+		text = fmt.Sprintf("First words will be labelled %s and second words %s.", label1, label2)
+	}
+	reply(ctx, b, update.Message, text)
+}
+
+func HandleSetSeparator(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleSetSeparator")
+		return
+	}
+
+	separator := ""
+	if parts := strings.Fields(update.Message.Text); len(parts) == 2 {
+		separator = strings.ToLower(parts[1])
+	}
+	if _, ok := separators[separator]; !ok {
+		reply(ctx, b, update.Message, "Please use the format: /setseparator auto|tab|comma|semicolon\n\nTo choose the column separator of uploaded CSV files.")
+		return
+	}
+
+	if err := updateSettings(update.Message.From.ID, map[string]interface{}{"import_separator": separator}); err != nil {
+		internalError(ctx, b, update.Message, "Failed to update settings. Please try again.", "failed to update user settings", err)
+		return
+	}
+
+	text := fmt.Sprintf("Uploaded files will be split by %s.", separator)
+	if separator == SeparatorAuto {
+		text = "The separator of uploaded files will be detected automatically."
+	}
+	reply(ctx, b, update.Message, text)
+}
+
+func HandleWhoAmI(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleWhoAmI")
+		return
+	}
+
+	text := fmt.Sprintf("User ID: %d\nChat ID: %d\nChat type: %s", update.Message.From.ID, update.Message.Chat.ID, update.Message.Chat.Type)
+
+	var settings db.UserSettings
+	err := settingsQuery(update.Message.From.ID).First(&settings).Error
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		text += "\nSettings: none yet, say /start"
+	case err != nil:
+		logger.Error("failed to fetch user settings", "user_id", update.Message.From.ID, "error", err)
+	default:
+		text += fmt.Sprintf("\nSettings: %d pairs, %d reminders per day", settings.PairsToSend, settings.RemindersPerDay)
+	}
+
+	reply(ctx, b, update.Message, text)
+}
+
+func HandleLastReminder(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleLastReminder")
+		return
+	}
+
+	var settings db.UserSettings
+	if err := settingsQuery(update.Message.From.ID).First(&settings).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			reply(ctx, b, update.Message, "You have no settings yet, so no reminders are sent. Say /start to begin.")
+			return
+		}
+		internalError(ctx, b, update.Message, "Failed to fetch your settings. Please try again later.", "failed to fetch user settings", err)
+		return
+	}
+
+	var pairsCount int64
+	if err := db.DB.Model(&db.WordPair{}).Where("user_id = ? AND word2 <> ''", update.Message.From.ID).Count(&pairsCount).Error; err != nil {
+		internalError(ctx, b, update.Message, "Failed to count your word pairs. Please try again later.", "failed to count word pairs", err)
+		return
+	}
+
+	reply(ctx, b, update.Message, lastReminderText(settings, pairsCount, time.Now()))
+}
+
+// lastReminderText describes the user's last reminder and what keeps the next ones from being sent at now
+func lastReminderText(settings db.UserSettings, pairsCount int64, now time.Time) string {
+	const layout = "2006-01-02 15:04"
+	var sb strings.Builder
+	if settings.LastReminderAt != nil {
+		fmt.Fprintf(&sb, "Last reminder: %s\n", settings.LastReminderAt.In(now.Location()).Format(layout))
+	} else {
+		sb.WriteString("Last reminder: none yet\n")
+	}
+	fmt.Fprintf(&sb, "Reminders per day: %d, with %d pairs each\n", settings.RemindersPerDay, settings.PairsToSend)
+	if pairsCount == 0 {
+		sb.WriteString("\nYou have no word pairs with a translation, so reminders are not sent.\n")
+	}
+	if onVacation(settings, now) {
+		fmt.Fprintf(&sb, "\nYou are on vacation until %s. Use /vacation 0 to resume now.\n", settings.ResumeAt.In(now.Location()).Format(layout))
+	}
+	if settings.WeekdaysOnly {
+		sb.WriteString("\nReminders are sent on weekdays only")
+		if isWeekend(now) {
+			sb.WriteString(", so none are sent today")
+		}
+		sb.WriteString(".\n")
+	}
+	return sb.String()
 }
 
 func HandleGetPair(ctx context.Context, b *bot.Bot, update *models.Update) {
@@ -263,30 +518,34 @@ func HandleGetPair(ctx context.Context, b *bot.Bot, update *models.Update) {
 	}
 
 	var wordPair db.WordPair
-	if err := db.DB.Where("user_id = ?", update.Message.From.ID).Order("RANDOM()").Limit(1).Find(&wordPair).Error; err != nil {
-		logger.Error("failed to fetch random word pair for user", "user_id", update.Message.From.ID, "error", err)
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "Failed to retrieve a word pair. Please try again later.",
-		})
+	result := db.DB.Where("user_id = ? AND word2 <> ''", update.Message.From.ID).Order("RANDOM()").Limit(1).Find(&wordPair)
+	if result.Error != nil {
+		internalError(ctx, b, update.Message, "Failed to retrieve a word pair. Please try again later.", "failed to fetch random word pair for user", result.Error)
 		return
 	}
 
-	if (wordPair == db.WordPair{}) {
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   "You have no word pairs saved. Please upload some word pairs first.",
-		})
+	if result.RowsAffected == 0 {
+		text := "You have no word pairs saved. Please upload some word pairs first."
+		var pending int64
+		if err := db.DB.Model(&db.WordPair{}).Where("user_id = ? AND word2 = ''", update.Message.From.ID).Count(&pending).Error; err != nil {
+			logger.Error("failed to count pending words", "user_id", update.Message.From.ID, "error", err)
+		} else if pending > 0 {
+			text = fmt.Sprintf("None of your %d saved words has a translation yet. Say /fill to see them.", pending)
+		}
+		reply(ctx, b, update.Message, text)
 		return
 	}
 
-	message := PrepareWordPairMessage(wordPair.Word1, wordPair.Word2)
+	var settings db.UserSettings
+	if err := settingsQuery(update.Message.From.ID).First(&settings).Error; err != nil && err != gorm.ErrRecordNotFound {
+		logger.Error("failed to fetch user settings", "user_id", update.Message.From.ID, "error", err)
+	}
 
-	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:    update.Message.Chat.ID,
-		Text:      message,
-		ParseMode: models.ParseModeMarkdown,
-	})
+	message := PrepareWordPairMessage(wordPair.Word1, wordPair.Word2, settings)
+
+	params := replyParams(update.Message, message)
+	params.ParseMode = models.ParseModeMarkdown
+	_, err := b.SendMessage(ctx, params)
 	if err != nil {
 		logger.Error("failed to send random word pair message", "user_id", update.Message.From.ID, "error", err)
 	}
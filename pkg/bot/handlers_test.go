@@ -0,0 +1,202 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/db"
+)
+
+func TestHandleGetPair(t *testing.T) {
+	tests := []struct {
+		name    string
+		pair    *db.WordPair // Pair found by the query, if any
+		pending int64
+		want    string
+	}{
+		{
+			name: "pair with an empty first word",
+			pair: &db.WordPair{UserID: 1, Word2: "house"},
+			want: "||",
+		},
+		{
+			name:    "only pending words",
+			pending: 2,
+			want:    "None of your 2 saved words has a translation yet. Say /fill to see them.",
+		},
+		{
+			name: "no words",
+			want: "You have no word pairs saved.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements := useDryRunDB(t)
+			statements.result = func(s statement) int64 {
+				switch dest := s.Dest.(type) {
+				case *db.WordPair:
+					if tt.pair == nil {
+						return 0
+					}
+					*dest = *tt.pair
+					return 1
+				case *int64:
+					*dest = tt.pending
+					return 1
+				}
+				return 0
+			}
+			b, fake := newTestBot(t)
+
+			HandleGetPair(context.Background(), b, &models.Update{Message: testMessage("/getpair")})
+
+			sent := fake.sent()
+			if len(sent) != 1 || !strings.Contains(sent[0], tt.want) {
+				t.Errorf("sent %q, want a message containing %q", sent, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleSetPairs(t *testing.T) {
+	tests := []struct {
+		text      string
+		wantPairs int // Stored pairs count, 0 when nothing is stored
+		wantReply string
+	}{
+		{"/setpairs 5", 5, "Number of pairs in each reminder has been set to 5."},
+		{"/setpairs 10", 10, "Number of pairs in each reminder has been set to 10."},
+		{"/setpairs 11", 0, "Please provide a valid number of pairs in each reminder (1-10)."},
+		{"/setpairs 0", 0, "Please provide a valid number of pairs in each reminder (1-10)."},
+		{"/setpairs many", 0, "Please provide a valid number of pairs in each reminder (1-10)."},
+		{"/setpairs", 0, "Please use the format: /setnum <number>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			statements := useDryRunDB(t)
+			b, fake := newTestBot(t)
+
+			HandleSetNumOfPairs(context.Background(), b, &models.Update{Message: testMessage(tt.text)})
+
+			stored := 0
+			for _, s := range statements.all() {
+				if settings, ok := s.Dest.(*db.UserSettings); ok && strings.HasPrefix(s.SQL, "INSERT") {
+					stored = settings.PairsToSend
+				}
+			}
+			if stored != tt.wantPairs {
+				t.Errorf("stored %d pairs, want %d", stored, tt.wantPairs)
+			}
+			if sent := fake.sent(); len(sent) != 1 || !strings.HasPrefix(sent[0], tt.wantReply) {
+				t.Errorf("replied %q, want %q", sent, tt.wantReply)
+			}
+		})
+	}
+}
+
+func TestLastReminderText(t *testing.T) {
+	monday := time.Date(2024, 7, 15, 10, 0, 0, 0, time.UTC)
+	saturday := time.Date(2024, 7, 13, 10, 0, 0, 0, time.UTC)
+	lastReminder := time.Date(2024, 7, 15, 8, 30, 0, 0, time.UTC)
+	resumeAt := time.Date(2024, 7, 20, 9, 0, 0, 0, time.UTC)
+	base := db.UserSettings{UserID: 1, PairsToSend: 3, RemindersPerDay: 4, LastReminderAt: &lastReminder}
+
+	tests := []struct {
+		name       string
+		settings   db.UserSettings
+		pairsCount int64
+		now        time.Time
+		want       string
+	}{
+		{
+			name:       "sending",
+			settings:   base,
+			pairsCount: 12,
+			now:        monday,
+			want:       "Last reminder: 2024-07-15 08:30\nReminders per day: 4, with 3 pairs each\n",
+		},
+		{
+			name:       "never reminded",
+			settings:   db.UserSettings{PairsToSend: 1, RemindersPerDay: 1},
+			pairsCount: 12,
+			now:        monday,
+			want:       "Last reminder: none yet\nReminders per day: 1, with 1 pairs each\n",
+		},
+		{
+			name:     "no pairs",
+			settings: base,
+			now:      monday,
+			want:     "Last reminder: 2024-07-15 08:30\nReminders per day: 4, with 3 pairs each\n\nYou have no word pairs with a translation, so reminders are not sent.\n",
+		},
+		{
+			name: "vacation",
+			settings: func() db.UserSettings {
+				s := base
+				s.ResumeAt = &resumeAt
+				return s
+			}(),
+			pairsCount: 12,
+			now:        monday,
+			want:       "Last reminder: 2024-07-15 08:30\nReminders per day: 4, with 3 pairs each\n\nYou are on vacation until 2024-07-20 09:00. Use /vacation 0 to resume now.\n",
+		},
+		{
+			name: "weekend",
+			settings: func() db.UserSettings {
+				s := base
+				s.WeekdaysOnly = true
+				return s
+			}(),
+			pairsCount: 12,
+			now:        saturday,
+			want:       "Last reminder: 2024-07-15 08:30\nReminders per day: 4, with 3 pairs each\n\nReminders are sent on weekdays only, so none are sent today.\n",
+		},
+		{
+			name: "weekdays only on a weekday",
+			settings: func() db.UserSettings {
+				s := base
+				s.WeekdaysOnly = true
+				return s
+			}(),
+			pairsCount: 12,
+			now:        monday,
+			want:       "Last reminder: 2024-07-15 08:30\nReminders per day: 4, with 3 pairs each\n\nReminders are sent on weekdays only.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lastReminderText(tt.settings, tt.pairsCount, tt.now); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleSwap(t *testing.T) {
+	statements := useDryRunDB(t)
+	statements.result = func(s statement) int64 {
+		switch dest := s.Dest.(type) {
+		case *int64:
+			*dest = 2 // Pending words
+			return 1
+		}
+		if strings.HasPrefix(s.SQL, "UPDATE") {
+			return 5
+		}
+		return 0
+	}
+	b, fake := newTestBot(t)
+
+	HandleSwap(context.Background(), b, &models.Update{Message: testMessage("/swap")})
+
+	want := `UPDATE "word_pairs" SET "word1"=word2,"word2"=word1 WHERE user_id = 1 AND word2 <> ''`
+	if all := statements.all(); len(all) == 0 || all[0].SQL != want {
+		t.Errorf("swapped with %+v, want %s", all, want)
+	}
+	sent := fake.sent()
+	if len(sent) != 1 || !strings.HasPrefix(sent[0], "Swapped the words in 5 pairs.") || !strings.Contains(sent[0], "2 words without a translation were left untouched.") {
+		t.Errorf("unexpected reply %q", sent)
+	}
+}
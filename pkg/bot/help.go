@@ -0,0 +1,228 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/logger"
+)
+
+type helpEntry struct {
+	Summary string
+	Details string
+}
+
+// helpCommands keeps the order in which commands are listed by /help
+var helpCommands = []string{"getpair", "add", "addbulk", "fill", "setnum", "setfreq", "weekdays", "vacation", "wotd", "hide", "labels", "setseparator", "swap", "clear", "lastreminder", "whoami", "help"}
+
+var helpEntries = map[string]helpEntry{
+	"getpair": {
+		Summary: "Get a random word pair.",
+		Details: "Usage: /getpair\n\nSends one random pair from your vocabulary with one of the words hidden under a spoiler.",
+	},
+	"add": {
+		Summary: "Add a single word pair.",
+		Details: "Usage: /add <word1> = <word2>\n\nAdds the pair, or replaces the second word if you already have a pair with the same first word.\nExample: /add huis = house",
+	},
+	"addbulk": {
+		Summary: "Add word pairs pasted into the message.",
+		Details: "Usage: /addbulk followed by one pair per line, in the same format as an uploaded file: separated by a tab, a semicolon or a comma, with quotes around words containing the separator.\nExample:\n/addbulk\nhuis, house\nkat, cat",
+	},
+	"fill": {
+		Summary: "List words that still need a translation.",
+		Details: "Usage: /fill\n\nLines with a single word in an upload are kept without a translation and are not sent in reminders. /fill lists them; add a translation with /add <word> = <translation>.",
+	},
+	"setnum": {
+		Summary: "Set the number of pairs in each reminder.",
+		Details: fmt.Sprintf("Usage: /setnum <number>\n\nSets how many pairs each reminder contains, from 1 to %d.\nExample: /setnum 3\n\nSend /setnum without a number to pick from presets. /setpairs works the same way.", MaxPairsPerReminder),
+	},
+	"setfreq": {
+		Summary: "Set the number of reminders per day.",
+		Details: fmt.Sprintf("Usage: /setfreq <number>\n\nSets how many reminders you get per day, from 1 to %d. They are spread evenly over the day.\nExample: /setfreq 4", maxRemindersPerDay),
+	},
+	"weekdays": {
+		Summary: "Turn weekend reminders on or off.",
+		Details: "Usage: /weekdays on|off\n\nWith \"on\", reminders are skipped on Saturdays and Sundays. /getpair keeps working every day.\n\nThe days follow the bot server's timezone, which may differ from yours.",
+	},
+	"vacation": {
+		Summary: "Pause reminders for a number of days.",
+		Details: "Usage: /vacation <days>\n\nPauses reminders and resumes them automatically once the days have passed.\nExample: /vacation 7\n\nUse /vacation 0 to resume right away.",
+	},
+	"wotd": {
+		Summary: "Turn the daily word of the day on or off.",
+		Details: "Usage: /wotd on|off\n\nWith \"on\", you get one random pair from your vocabulary once a day, in addition to the regular reminders.",
+	},
+	"hide": {
+		Summary: "Choose which word of a pair is hidden.",
+		Details: "Usage: /hide first|second|random|none\n\nSets which word is hidden under the spoiler in /getpair and in reminders, so you can practice one direction. The default is random. With \"none\", both words are shown, which works better with screen readers.\nExample: /hide second",
+	},
+	"labels": {
+		Summary: "Name the two word columns.",
+		Details: fmt.Sprintf("Usage: /labels <label1> <label2>\n\nShows the labels before the words in /getpair and in reminders, up to %d characters each.\nExample: /labels Dutch English\n\nUse /labels off to remove them.", maxLabelLength),
+	},
+	"setseparator": {
+		Summary: "Choose the column separator of uploaded files.",
+		Details: "Usage: /setseparator auto|tab|comma|semicolon\n\nWith \"auto\" (the default), the separator is detected from the first line of the file. Pick one explicitly if your words contain commas or semicolons.\nExample: /setseparator semicolon",
+	},
+	"swap": {
+		Summary: "Swap the words of all your pairs.",
+		Details: "Usage: /swap\n\nExchanges the first and second word of every pair, e.g. when a file was uploaded with the columns reversed.",
+	},
+	"clear": {
+		Summary: "Delete all your word pairs.",
+		Details: "Usage: /clear\n\nRemoves your whole vocabulary. This cannot be undone.",
+	},
+	"lastreminder": {
+		Summary: "Show when the last reminder was sent and what may hold them back.",
+		Details: "Usage: /lastreminder\n\nShows the time of your last reminder, your reminder settings, and whether a vacation, weekend or missing vocabulary is keeping reminders from being sent.",
+	},
+	"whoami": {
+		Summary: "Show your user and chat IDs.",
+		Details: "Usage: /whoami\n\nShows your user ID, the chat ID and type, and a summary of your settings. Useful when reporting a problem.",
+	},
+	"help": {
+		Summary: "Show the list of commands or help for one command.",
+		Details: "Usage: /help [command]\n\nExample: /help setnum",
+	},
+}
+
+// commandAliases maps common alternative names to the actual commands
+var commandAliases = map[string]string{
+	"pair":     "getpair",
+	"get":      "getpair",
+	"num":      "setnum",
+	"freq":     "setfreq",
+	"pause":    "vacation",
+	"holiday":  "vacation",
+	"commands": "help",
+}
+
+// CommandHandlers maps every command to its handler. main registers each of them with MatchCommand.
+var CommandHandlers = map[string]bot.HandlerFunc{
+	"start":        HandleStart,
+	"help":         HandleHelp,
+	"add":          HandleAdd,
+	"addbulk":      HandleAddBulk,
+	"fill":         HandleFill,
+	"clear":        HandleClear,
+	"swap":         HandleSwap,
+	"setnum":       HandleSetNumOfPairs,
+	"setpairs":     HandleSetNumOfPairs,
+	"setfreq":      HandleSetFrequency,
+	"weekdays":     HandleWeekdays,
+	"vacation":     HandleVacation,
+	"wotd":         HandleWordOfTheDay,
+	"hide":         HandleHide,
+	"labels":       HandleLabels,
+	"setseparator": HandleSetSeparator,
+	"lastreminder": HandleLastReminder,
+	"whoami":       HandleWhoAmI,
+	"getpair":      HandleGetPair,
+}
+
+// commandName splits a "/command args" message text into the lowercase command name,
+// without the "@BotName" suffix commands carry in groups, and the rest of the text
+func commandName(text string) (name, rest string, ok bool) {
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+	command := text
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		command, rest = text[:i], text[i:]
+	}
+	name = strings.ToLower(strings.TrimPrefix(command, "/"))
+	name, _, _ = strings.Cut(name, "@")
+	return name, rest, name != ""
+}
+
+// MatchCommand returns a matcher for messages invoking the named command,
+// including /name@BotName as sent in groups and the command typed in another case
+func MatchCommand(name string) bot.MatchFunc {
+	return func(update *models.Update) bool {
+		if update.Message == nil {
+			return false
+		}
+		command, _, ok := commandName(update.Message.Text)
+		return ok && command == name
+	}
+}
+
+// NormalizeCommand rewrites a command message to the plain lowercase /command form the handlers parse
+func NormalizeCommand(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		if update.Message != nil {
+			if name, rest, ok := commandName(update.Message.Text); ok {
+				message := *update.Message
+				message.Text = "/" + name + rest
+				normalized := *update
+				normalized.Message = &message
+				update = &normalized
+			}
+		}
+		next(ctx, b, update)
+	}
+}
+
+// suggestCommand returns the known command closest to an unrecognized "/command" message,
+// if it is an alias or within two edits of a known command
+func suggestCommand(text string) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", false
+	}
+	name := strings.ToLower(strings.TrimPrefix(fields[0], "/"))
+	name, _, _ = strings.Cut(name, "@") // Commands in groups may carry the bot username
+	if name == "" {
+		return "", false
+	}
+
+	if command, ok := commandAliases[name]; ok {
+		return command, true
+	}
+
+	best, bestDistance := "", 3
+	for _, command := range append([]string{"start"}, helpCommands...) {
+		if d := levenshtein([]rune(name), []rune(command)); d > 0 && d < bestDistance { // Never suggest exactly what was typed
+			best, bestDistance = command, d
+		}
+	}
+	return best, best != ""
+}
+
+// helpOverview lists every command with its short summary
+func helpOverview() string {
+	var sb strings.Builder
+	sb.WriteString("Available commands:\n\n")
+	for _, name := range helpCommands {
+		fmt.Fprintf(&sb, "/%s - %s\n", name, helpEntries[name].Summary)
+	}
+	sb.WriteString("\nYou can also attach a CSV file with word pairs separated by tabs, commas or semicolons to upload them. See /help setseparator.\nSay /help <command> for details on a command.")
+	return sb.String()
+}
+
+func HandleHelp(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleHelp")
+		return
+	}
+
+	text := helpOverview()
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) > 1 {
+		name := strings.ToLower(strings.TrimPrefix(parts[1], "/"))
+		if entry, ok := helpEntries[name]; ok {
+			text = entry.Details
+		} else {
+			text = fmt.Sprintf("Unknown command: %s\n\n%s", parts[1], text)
+		}
+	}
+
+	_, err := reply(ctx, b, update.Message, text)
+	if err != nil {
+		logger.Error("failed to send help message", "error", err)
+	}
+}
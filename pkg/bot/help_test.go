@@ -0,0 +1,178 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func TestSuggestCommand(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{"/getpiar", "getpair", true},
+		{"/setfrq 3", "setfreq", true},
+		{"/Wotd@MyBot", "", false}, // Never suggests exactly what was typed
+		{"/pause 3", "vacation", true},
+		{"/xyzzyplugh", "", false},
+		{"hello", "", false},
+		{"/", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := suggestCommand(tt.text)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("suggestCommand(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestMatchCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"whoami", "/whoami", true},
+		{"whoami", "/whoami@MyBot", true},
+		{"getpair", "/GetPair", true},
+		{"add", "/add huis = house", true},
+		{"add", "/addbulk\nhuis, house", false},
+		{"addbulk", "/addbulk\nhuis, house", true},
+		{"getpair", "/getpiar", false},
+		{"whoami", "whoami", false},
+	}
+	for _, tt := range tests {
+		update := &models.Update{Message: testMessage(tt.text)}
+		if got := MatchCommand(tt.name)(update); got != tt.want {
+			t.Errorf("MatchCommand(%q) on %q = %v, want %v", tt.name, tt.text, got, tt.want)
+		}
+	}
+	if MatchCommand("setnum")(&models.Update{CallbackQuery: &models.CallbackQuery{Data: "setnum:3"}}) {
+		t.Error("MatchCommand matched a callback query")
+	}
+}
+
+func TestNormalizeCommand(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"/whoami@MyBot", "/whoami"},
+		{"/GetPair", "/getpair"},
+		{"/add@MyBot huis = house", "/add huis = house"},
+		{"/addbulk@MyBot\nhuis, house", "/addbulk\nhuis, house"},
+	}
+	for _, tt := range tests {
+		var got string
+		handler := NormalizeCommand(func(ctx context.Context, b *bot.Bot, update *models.Update) {
+			got = update.Message.Text
+		})
+		update := &models.Update{ID: 1, Message: testMessage(tt.text)}
+		handler(context.Background(), nil, update)
+		if got != tt.want {
+			t.Errorf("NormalizeCommand(%q) passed %q, want %q", tt.text, got, tt.want)
+		}
+		if update.Message.Text != tt.text {
+			t.Errorf("NormalizeCommand modified the original update to %q", update.Message.Text)
+		}
+	}
+}
+
+func TestEveryHelpCommandHasHandler(t *testing.T) {
+	for _, name := range helpCommands {
+		if _, ok := CommandHandlers[name]; !ok {
+			t.Errorf("/%s is listed in /help but has no handler", name)
+		}
+		if _, ok := helpEntries[name]; !ok {
+			t.Errorf("/%s is listed in /help but has no help entry", name)
+		}
+	}
+	for name := range CommandHandlers {
+		if _, ok := helpEntries[name]; !ok && name != "start" && name != "setpairs" {
+			t.Errorf("/%s has a handler but no help entry", name)
+		}
+	}
+}
+
+func TestDefaultHandlerReplies(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *models.Message
+		want    string
+		notWant string
+	}{
+		{"near miss", testMessage("/getpiar"), "Did you mean /getpair?", ""},
+		{"unrelated command", testMessage("/xyzzyplugh"), "Say /help to see the available commands.", "Did you mean"},
+		{"sticker", func() *models.Message {
+			msg := testMessage("")
+			msg.Sticker = &models.Sticker{FileID: "sticker", Emoji: "👍"}
+			return msg
+		}(), "I didn't understand that kind of message. Send /help to see what I can do.", "Did you mean"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useDryRunDB(t)
+			b, fake := newTestBot(t)
+
+			DefaultHandler(context.Background(), b, &models.Update{Message: tt.message})
+
+			sent := strings.Join(fake.sent(), "\n")
+			if !strings.Contains(sent, tt.want) {
+				t.Errorf("reply %q does not contain %q", sent, tt.want)
+			}
+			if tt.notWant != "" && strings.Contains(sent, tt.notWant) {
+				t.Errorf("reply %q contains %q", sent, tt.notWant)
+			}
+		})
+	}
+}
+
+func TestCommandInGroupReachesHandler(t *testing.T) {
+	useDryRunDB(t)
+	b, fake := newTestBot(t)
+	// The same registration as in main
+	for name, handler := range CommandHandlers {
+		b.RegisterHandlerMatchFunc(MatchCommand(name), handler, NormalizeCommand)
+	}
+
+	b.ProcessUpdate(context.Background(), &models.Update{Message: testMessage("/whoami@MyBot")})
+
+	sent := strings.Join(fake.sent(), "\n")
+	if !strings.Contains(sent, "User ID: 1") || strings.Contains(sent, "Did you mean") {
+		t.Errorf("unexpected reply %q", sent)
+	}
+}
+
+func TestHandleHelp(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"/help", []string{"Available commands:", "/getpair - Get a random word pair."}},
+		{"/help setnum", []string{helpEntries["setnum"].Details}},
+		{"/help /wotd", []string{helpEntries["wotd"].Details}},
+		{"/help nope", []string{"Unknown command: nope", "Available commands:"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			b, fake := newTestBot(t)
+
+			HandleHelp(context.Background(), b, &models.Update{Message: testMessage(tt.text)})
+
+			sent := fake.sent()
+			if len(sent) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(sent))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(sent[0], want) {
+					t.Errorf("reply %q does not contain %q", sent[0], want)
+				}
+			}
+		})
+	}
+}
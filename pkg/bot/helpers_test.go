@@ -0,0 +1,159 @@
+package bot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/db"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// apiCall is one request made to the fake Telegram API
+type apiCall struct {
+	Method string
+	Params map[string]string
+}
+
+// fakeTelegram records the API calls of a test bot and answers them with success
+type fakeTelegram struct {
+	mu    sync.Mutex
+	calls []apiCall
+	// respond, when set, may replace the response to a call; it returns "" to keep the default
+	respond func(method string, n int) string
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := path.Base(r.URL.Path)
+	params := make(map[string]string)
+	if err := r.ParseMultipartForm(1 << 20); err == nil {
+		for key, values := range r.MultipartForm.Value {
+			params[key] = values[0]
+		}
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, apiCall{Method: method, Params: params})
+	n := len(f.calls)
+	respond := f.respond
+	f.mu.Unlock()
+
+	if respond != nil {
+		if body := respond(method, n); body != "" {
+			fmt.Fprint(w, body)
+			return
+		}
+	}
+	if method == "sendMessage" {
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d,"date":0,"chat":{"id":%s,"type":"private"}}}`, n, params["chat_id"])
+		return
+	}
+	fmt.Fprint(w, `{"ok":true,"result":true}`)
+}
+
+// sent returns the texts of all messages sent so far
+func (f *fakeTelegram) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var texts []string
+	for _, call := range f.calls {
+		if call.Method == "sendMessage" {
+			texts = append(texts, call.Params["text"])
+		}
+	}
+	return texts
+}
+
+// newTestBot returns a bot talking to a fake Telegram API
+func newTestBot(t *testing.T) (*bot.Bot, *fakeTelegram) {
+	t.Helper()
+	fake := &fakeTelegram{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	b, err := bot.New("test-token", bot.WithSkipGetMe(), bot.WithServerURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create test bot: %v", err)
+	}
+	return b, fake
+}
+
+// testMessage returns a private message from user 1 with the given text
+func testMessage(text string) *models.Message {
+	return &models.Message{
+		Chat: models.Chat{ID: 1, Type: models.ChatTypePrivate},
+		From: &models.User{ID: 1},
+		Text: text,
+	}
+}
+
+// statement is one SQL statement built against the dry-run database
+type statement struct {
+	SQL  string
+	Dest any
+}
+
+// statementLog collects the statements built against the dry-run database
+type statementLog struct {
+	mu         sync.Mutex
+	statements []statement
+	// result, when set, stands in for the database: it may fill s.Dest and returns the rows affected
+	result func(s statement) int64
+}
+
+func (l *statementLog) record(tx *gorm.DB) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := statement{
+		SQL:  tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...),
+		Dest: tx.Statement.Dest,
+	}
+	l.statements = append(l.statements, s)
+	if l.result != nil {
+		tx.RowsAffected = l.result(s)
+	}
+}
+
+// all returns the statements recorded so far
+func (l *statementLog) all() []statement {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]statement(nil), l.statements...)
+}
+
+// useDryRunDB points db.DB at a Postgres dialect that builds statements without running them.
+// Queries find no rows and writes affect none unless the log's result says otherwise,
+// so handlers can be exercised without a database.
+func useDryRunDB(t *testing.T) *statementLog {
+	t.Helper()
+	gdb, err := gorm.Open(postgres.Open("host=localhost dbname=test"), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("failed to open dry-run database: %v", err)
+	}
+
+	log := &statementLog{}
+	callbacks := gdb.Callback()
+	for name, processor := range map[string]interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}{
+		"gorm:create": callbacks.Create().After("gorm:create"),
+		"gorm:query":  callbacks.Query().After("gorm:query"),
+		"gorm:update": callbacks.Update().After("gorm:update"),
+		"gorm:delete": callbacks.Delete().After("gorm:delete"),
+	} {
+		if err := processor.Register("test:record_"+name, log.record); err != nil {
+			t.Fatalf("failed to register callback: %v", err)
+		}
+	}
+
+	previous := db.DB
+	db.DB = gdb
+	t.Cleanup(func() { db.DB = previous })
+	return log
+}
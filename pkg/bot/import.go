@@ -0,0 +1,230 @@
+package bot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/db"
+	"github.com/smith3v/tg-word-reminder/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// importRecords stores the word pairs from records for the sender of msg and reports the result.
+// format describes the expected record layout in the invalid record message.
+func importRecords(ctx context.Context, b *bot.Bot, msg *models.Message, records [][]string, format string) {
+	// Existing first words are only used to warn about likely misspelled duplicates
+	var existingWords []string
+	if err := db.DB.Model(&db.WordPair{}).Where("user_id = ?", msg.From.ID).Pluck("word1", &existingWords).Error; err != nil {
+		logger.Error("failed to fetch existing words for duplicate check", "user_id", msg.From.ID, "error", err)
+	}
+	added, skipped, pending, nearDuplicates, sameLanguage := 0, 0, 0, 0, 0
+
+	// Process each record
+	for _, record := range records {
+		if isPendingWord(record) {
+			record = append(record, "") // A word without translation is kept as pending until /add fills it in
+		}
+		if len(record) != 2 {
+			skipped++
+			reply(ctx, b, msg, fmt.Sprintf("Invalid format in record: %v. Please use '%s' format.", record, format))
+			continue
+		}
+		wordPair := db.WordPair{
+			UserID: msg.From.ID,
+			Word1:  strings.TrimSpace(record[0]),
+			Word2:  strings.TrimSpace(record[1]),
+		}
+		if utf8.RuneCountInString(wordPair.Word1) > MaxWordLength || utf8.RuneCountInString(wordPair.Word2) > MaxWordLength {
+			skipped++
+			start := []rune(wordPair.Word1)
+			if len(start) > 20 {
+				start = start[:20]
+			}
+			reply(ctx, b, msg, fmt.Sprintf("Skipped the pair starting with %q: words can be at most %d characters long.", string(start), MaxWordLength))
+			continue
+		}
+		if hasNearDuplicate(wordPair.Word1, existingWords) {
+			nearDuplicates++
+		}
+		if looksSameLanguage(wordPair.Word1, wordPair.Word2) {
+			sameLanguage++
+		}
+		if err := db.DB.Create(&wordPair).Error; err != nil {
+			skipped++
+			internalError(ctx, b, msg, fmt.Sprintf("Failed to upload word pair: %v", record), "failed to create word pair", err)
+			continue
+		}
+		added++
+		if wordPair.Word2 == "" {
+			pending++
+		}
+	}
+
+	text := fmt.Sprintf("%d word pairs uploaded successfully.", added)
+	if skipped > 0 {
+		text += fmt.Sprintf(" Skipped: %d.", skipped)
+	}
+	if pending > 0 {
+		text += fmt.Sprintf("\n\n%d words have no translation yet and won't be sent until you add one. Say /fill to see them.", pending)
+	}
+	if nearDuplicates > 0 {
+		text += fmt.Sprintf("\n\nPossible near-duplicates of your existing words: %d. You may want to check their spelling.", nearDuplicates)
+	}
+	if added > 0 && sameLanguage*2 > added {
+		text += "\n\nBoth columns look like the same language. Did you mean to include a translation?"
+	}
+	reply(ctx, b, msg, text)
+}
+
+// isPendingWord reports whether record holds a single word without translation.
+// A single field containing a separator more likely means the file uses another separator than expected.
+func isPendingWord(record []string) bool {
+	return len(record) == 1 && strings.TrimSpace(record[0]) != "" && !strings.ContainsAny(record[0], "\t;,")
+}
+
+// SeparatorAuto detects the separator of an uploaded file from its first line
+const SeparatorAuto = "auto"
+
+// separators maps the names accepted by /setseparator to CSV delimiters
+var separators = map[string]rune{
+	SeparatorAuto: 0,
+	"tab":         '\t',
+	"comma":       ',',
+	"semicolon":   ';',
+}
+
+// importSeparator returns the delimiter for a file starting with head.
+// With an unknown or automatic setting it prefers a tab, then a semicolon, then a comma on the first line.
+func importSeparator(setting string, head []byte) rune {
+	if comma := separators[setting]; comma != 0 {
+		return comma
+	}
+	firstLine, _, _ := bytes.Cut(head, []byte("\n"))
+	switch {
+	case bytes.ContainsRune(firstLine, '\t'):
+		return '\t'
+	case bytes.ContainsRune(firstLine, ';'):
+		return ';'
+	case bytes.ContainsRune(firstLine, ','):
+		return ','
+	}
+	return '\t'
+}
+
+// parseVocabularyCSV reads all records from r using the given delimiter.
+// Quoted fields may contain the delimiter and newlines, e.g. "hello, world" stays one field.
+func parseVocabularyCSV(r io.Reader, comma rune) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1 // Records with a wrong number of fields are reported one by one by importRecords
+	reader.LazyQuotes = true    // Allow quotes inside unquoted words, e.g. 'say "hi"'
+	return reader.ReadAll()
+}
+
+// readVocabulary parses the records of an uploaded or pasted vocabulary with the separator chosen by setting.
+// With automatic detection the separator is taken from the first line of r.
+func readVocabulary(r io.Reader, setting string) ([][]string, rune, error) {
+	body := bufio.NewReader(r)
+	head, _ := body.Peek(4096) // A short input is returned whole along with io.EOF
+	comma := importSeparator(setting, head)
+	records, err := parseVocabularyCSV(body, comma)
+	return records, comma, err
+}
+
+// importSeparatorSetting returns the user's /setseparator choice, or "" for automatic detection
+func importSeparatorSetting(userID int64) string {
+	var settings db.UserSettings
+	if err := settingsQuery(userID).First(&settings).Error; err != nil && err != gorm.ErrRecordNotFound {
+		logger.Error("failed to fetch user settings", "user_id", userID, "error", err)
+	}
+	return settings.ImportSeparator
+}
+
+func HandleAddBulk(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleAddBulk")
+		return
+	}
+
+	// Everything after the command line is the pasted vocabulary
+	_, pasted, _ := strings.Cut(update.Message.Text, "\n")
+	records, comma, err := readVocabulary(strings.NewReader(pasted), importSeparatorSetting(update.Message.From.ID))
+	if err != nil {
+		internalError(ctx, b, update.Message, "Failed to read the pasted word pairs. Please check their format.", "failed to parse pasted word pairs", err)
+		return
+	}
+	if len(records) == 0 {
+		reply(ctx, b, update.Message, "Please put the word pairs on the lines after the command, one pair per line:\n\n/addbulk\nhuis, house\nkat, cat")
+		return
+	}
+
+	importRecords(ctx, b, update.Message, records, fmt.Sprintf("word1%cword2", comma))
+}
+
+func HandleAdd(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleAdd")
+		return
+	}
+
+	args := strings.TrimSpace(strings.TrimPrefix(update.Message.Text, "/add"))
+	word1, word2, found := strings.Cut(args, "=")
+	word1, word2 = strings.TrimSpace(word1), strings.TrimSpace(word2)
+	if !found || word1 == "" || word2 == "" {
+		reply(ctx, b, update.Message, "Please use the format: /add <word1> = <word2>\n\nTo add a single word pair.")
+		return
+	}
+	if utf8.RuneCountInString(word1) > MaxWordLength || utf8.RuneCountInString(word2) > MaxWordLength {
+		reply(ctx, b, update.Message, fmt.Sprintf("Words can be at most %d characters long.", MaxWordLength))
+		return
+	}
+
+	// An existing pair with the same first word gets its translation replaced
+	result := db.DB.Model(&db.WordPair{}).Where("user_id = ? AND word1 = ?", update.Message.From.ID, word1).Update("word2", word2)
+	if result.Error != nil {
+		internalError(ctx, b, update.Message, "Failed to add the word pair. Please try again later.", "failed to update word pair", result.Error)
+		return
+	}
+
+	text := fmt.Sprintf("Updated: %s = %s", word1, word2)
+	if result.RowsAffected == 0 {
+		wordPair := db.WordPair{UserID: update.Message.From.ID, Word1: word1, Word2: word2}
+		if err := db.DB.Create(&wordPair).Error; err != nil {
+			internalError(ctx, b, update.Message, "Failed to add the word pair. Please try again later.", "failed to create word pair", err)
+			return
+		}
+		text = fmt.Sprintf("Added: %s = %s", word1, word2)
+	}
+
+	reply(ctx, b, update.Message, text)
+}
+
+// maxFillWords limits how many pending words /fill lists at once
+const maxFillWords = 20
+
+func HandleFill(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.Message == nil || update.Message.From == nil || update.Message.Chat.ID == 0 {
+		logger.Error("invalid update in HandleFill")
+		return
+	}
+
+	var words []string
+	if err := db.DB.Model(&db.WordPair{}).Where("user_id = ? AND word2 = ''", update.Message.From.ID).Order("id").Limit(maxFillWords).Pluck("word1", &words).Error; err != nil {
+		internalError(ctx, b, update.Message, "Failed to fetch your pending words. Please try again later.", "failed to fetch pending words", err)
+		return
+	}
+
+	text := "All your words have translations."
+	if len(words) > 0 {
+		text = fmt.Sprintf("Words without a translation:\n\n%s\n\nAdd a translation with /add <word> = <translation>, e.g. /add %s = ...", strings.Join(words, "\n"), words[0])
+	}
+	reply(ctx, b, update.Message, text)
+}
@@ -0,0 +1,273 @@
+package bot
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/db"
+)
+
+func TestImportRecordsKeepsSingleWordAsPending(t *testing.T) {
+	statements := useDryRunDB(t)
+	b, fake := newTestBot(t)
+
+	importRecords(context.Background(), b, testMessage(""), [][]string{{"huis"}, {"kat", "cat"}, {" "}}, "word1,word2")
+
+	created := createdPairs(statements)
+	want := []db.WordPair{{UserID: 1, Word1: "huis"}, {UserID: 1, Word1: "kat", Word2: "cat"}}
+	if len(created) != len(want) {
+		t.Fatalf("created %d pairs, want %d: %+v", len(created), len(want), created)
+	}
+	for i := range want {
+		if created[i] != want[i] {
+			t.Errorf("pair %d = %+v, want %+v", i, created[i], want[i])
+		}
+	}
+
+	sent := fake.sent()
+	summary := sent[len(sent)-1]
+	if !strings.Contains(summary, "2 word pairs uploaded") || !strings.Contains(summary, "1 words have no translation") {
+		t.Errorf("unexpected summary: %q", summary)
+	}
+}
+
+func TestPendingPairsAreExcludedFromSelection(t *testing.T) {
+	statements := useDryRunDB(t)
+	b, _ := newTestBot(t)
+
+	if _, err := selectRandomPairs(1, 5); err != nil {
+		t.Fatalf("selectRandomPairs: %v", err)
+	}
+	if sql := statements.all()[0].SQL; !strings.Contains(sql, "word2 <> ''") {
+		t.Errorf("selectRandomPairs does not skip pending pairs: %s", sql)
+	}
+
+	HandleFill(context.Background(), b, &models.Update{Message: testMessage("/fill")})
+	if sql := statements.all()[1].SQL; !strings.Contains(sql, "word2 = ''") {
+		t.Errorf("/fill does not list only pending pairs: %s", sql)
+	}
+
+	HandleSwap(context.Background(), b, &models.Update{Message: testMessage("/swap")})
+	if sql := statements.all()[2].SQL; !strings.HasPrefix(sql, "UPDATE") || !strings.Contains(sql, "word2 <> ''") {
+		t.Errorf("/swap does not skip pending pairs: %s", sql)
+	}
+}
+
+func TestParseVocabularyCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		comma rune
+		want  [][]string
+	}{
+		{
+			name:  "quoted comma",
+			input: "\"hello, world\",hallo wereld\n",
+			comma: ',',
+			want:  [][]string{{"hello, world", "hallo wereld"}},
+		},
+		{
+			name:  "quoted newline",
+			input: "\"line one\nline two\"\tregel\n",
+			comma: '\t',
+			want:  [][]string{{"line one\nline two", "regel"}},
+		},
+		{
+			name:  "bare quote",
+			input: "say \"hi\"\tzeg \"hoi\"\n",
+			comma: '\t',
+			want:  [][]string{{"say \"hi\"", "zeg \"hoi\""}},
+		},
+		{
+			name:  "mixed field counts",
+			input: "huis;house\nkat\nhond;dog;extra\n",
+			comma: ';',
+			want:  [][]string{{"huis", "house"}, {"kat"}, {"hond", "dog", "extra"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVocabularyCSV(strings.NewReader(tt.input), tt.comma)
+			if err != nil {
+				t.Fatalf("parseVocabularyCSV: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportSeparator(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		head    string
+		want    rune
+	}{
+		{"tab setting", "tab", "huis;house,home\n", '\t'},
+		{"comma setting", "comma", "huis\thouse\n", ','},
+		{"semicolon setting", "semicolon", "huis\thouse\n", ';'},
+		{"auto prefers tab", SeparatorAuto, "huis, woning;\thouse\n", '\t'},
+		{"auto prefers semicolon over comma", SeparatorAuto, "huis, woning;house\n", ';'},
+		{"auto falls back to comma", SeparatorAuto, "huis,house\n", ','},
+		{"auto looks at the first line only", SeparatorAuto, "huis,house\nkat\tcat\n", ','},
+		{"auto without separator", SeparatorAuto, "huis\n", '\t'},
+		{"unknown setting is auto", "", "huis;house\n", ';'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := importSeparator(tt.setting, []byte(tt.head)); got != tt.want {
+				t.Errorf("importSeparator(%q, %q) = %q, want %q", tt.setting, tt.head, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSemicolonFileKeepsCommasInWords(t *testing.T) {
+	input := "huis, woning;house, home\nkat;cat\n"
+	comma := importSeparator("semicolon", []byte(input))
+	got, err := parseVocabularyCSV(strings.NewReader(input), comma)
+	if err != nil {
+		t.Fatalf("parseVocabularyCSV: %v", err)
+	}
+	want := [][]string{{"huis, woning", "house, home"}, {"kat", "cat"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestImportRecordsReportsSeparatorMismatch(t *testing.T) {
+	statements := useDryRunDB(t)
+	b, fake := newTestBot(t)
+
+	input := "huis\thouse\nkat\tcat\n"
+	records, err := parseVocabularyCSV(strings.NewReader(input), importSeparator("comma", []byte(input)))
+	if err != nil {
+		t.Fatalf("parseVocabularyCSV: %v", err)
+	}
+	importRecords(context.Background(), b, testMessage(""), records, "word1,word2")
+
+	for _, s := range statements.all() {
+		if strings.HasPrefix(s.SQL, "INSERT") {
+			t.Errorf("stored a record parsed with the wrong separator: %s", s.SQL)
+		}
+	}
+	sent := fake.sent()
+	if len(sent) != 3 {
+		t.Fatalf("sent %d messages, want 2 invalid format reports and a summary: %q", len(sent), sent)
+	}
+	for _, text := range sent[:2] {
+		if !strings.HasPrefix(text, "Invalid format in record") {
+			t.Errorf("unexpected report %q", text)
+		}
+	}
+	if !strings.Contains(sent[2], "0 word pairs uploaded") || strings.Contains(sent[2], "no translation") {
+		t.Errorf("unexpected summary %q", sent[2])
+	}
+}
+
+// createdPairs returns the pairs inserted by the recorded statements
+func createdPairs(statements *statementLog) []db.WordPair {
+	var created []db.WordPair
+	for _, s := range statements.all() {
+		if pair, ok := s.Dest.(*db.WordPair); ok && strings.HasPrefix(s.SQL, "INSERT") {
+			created = append(created, *pair)
+		}
+	}
+	return created
+}
+
+func TestPastedPairsMatchUpload(t *testing.T) {
+	inputs := []string{
+		"\"hello, world\",hallo wereld\nkat,cat",
+		"huis\thouse\n\"say \"\"hi\"\"\"\tzeg hoi",
+		"huis;house, home\nkat;cat",
+	}
+	for _, input := range inputs {
+		uploaded, _, err := readVocabulary(strings.NewReader(input), SeparatorAuto)
+		if err != nil {
+			t.Fatalf("readVocabulary(%q): %v", input, err)
+		}
+
+		statements := useDryRunDB(t)
+		b, _ := newTestBot(t)
+		HandleAddBulk(context.Background(), b, &models.Update{Message: testMessage("/addbulk\n" + input)})
+
+		created := createdPairs(statements)
+		if len(created) != len(uploaded) {
+			t.Fatalf("pasting %q created %d pairs, uploading gives %d records", input, len(created), len(uploaded))
+		}
+		for i, record := range uploaded {
+			if created[i].Word1 != record[0] || created[i].Word2 != strings.TrimSpace(record[1]) {
+				t.Errorf("pasting %q created %+v, uploading gives %q", input, created[i], record)
+			}
+		}
+	}
+}
+
+func TestHandleAdd(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		existing   bool // Whether the user already has a pair starting with the first word
+		wantSQL    []string
+		wantCreate bool
+		wantReply  string
+	}{
+		{
+			name:       "new pair",
+			text:       "/add huis = house",
+			wantSQL:    []string{`UPDATE "word_pairs" SET "word2"='house' WHERE user_id = 1 AND word1 = 'huis'`, `INSERT INTO "word_pairs"`},
+			wantCreate: true,
+			wantReply:  "Added: huis = house",
+		},
+		{
+			name:      "existing first word",
+			text:      "/add huis = home",
+			existing:  true,
+			wantSQL:   []string{`UPDATE "word_pairs" SET "word2"='home' WHERE user_id = 1 AND word1 = 'huis'`},
+			wantReply: "Updated: huis = home",
+		},
+		{
+			name:      "missing equals sign",
+			text:      "/add huis house",
+			wantReply: "Please use the format: /add <word1> = <word2>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements := useDryRunDB(t)
+			statements.result = func(s statement) int64 {
+				if tt.existing && strings.HasPrefix(s.SQL, "UPDATE") {
+					return 1
+				}
+				return 0
+			}
+			b, fake := newTestBot(t)
+
+			HandleAdd(context.Background(), b, &models.Update{Message: testMessage(tt.text)})
+
+			all := statements.all()
+			if len(all) != len(tt.wantSQL) {
+				t.Fatalf("built %d statements, want %d: %+v", len(all), len(tt.wantSQL), all)
+			}
+			for i, want := range tt.wantSQL {
+				if !strings.HasPrefix(all[i].SQL, want) {
+					t.Errorf("statement %d = %s, want it to start with %s", i, all[i].SQL, want)
+				}
+			}
+			if tt.wantCreate {
+				if pair, ok := all[1].Dest.(*db.WordPair); !ok || *pair != (db.WordPair{UserID: 1, Word1: "huis", Word2: "house"}) {
+					t.Errorf("created %+v, want the new pair", all[1].Dest)
+				}
+			}
+			if sent := fake.sent(); len(sent) != 1 || !strings.HasPrefix(sent[0], tt.wantReply) {
+				t.Errorf("replied %q, want %q", sent, tt.wantReply)
+			}
+		})
+	}
+}
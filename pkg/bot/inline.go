@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/db"
+	"github.com/smith3v/tg-word-reminder/pkg/logger"
+)
+
+// maxInlineResults limits the number of pairs offered for a single inline query
+const maxInlineResults = 20
+
+// HandleInlineQuery looks up the querying user's pairs matching the typed text, so they can be shared in any chat
+func HandleInlineQuery(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update == nil || update.InlineQuery == nil || update.InlineQuery.From == nil {
+		logger.Error("invalid update in HandleInlineQuery")
+		return
+	}
+	query := update.InlineQuery
+
+	var wordPairs []db.WordPair
+	tx := db.DB.Where("user_id = ? AND word2 <> ''", query.From.ID) // Pending words have nothing to share yet
+	if text := strings.TrimSpace(query.Query); text != "" {
+		pattern := "%" + escapeLike(text) + "%"
+		tx = tx.Where("word1 ILIKE ? OR word2 ILIKE ?", pattern, pattern).Order("word1")
+	} else {
+		tx = tx.Order("RANDOM()")
+	}
+	if err := tx.Limit(maxInlineResults).Find(&wordPairs).Error; err != nil {
+		logger.Error("failed to search word pairs for inline query", "user_id", query.From.ID, "error", err)
+		return
+	}
+
+	results := make([]models.InlineQueryResult, 0, len(wordPairs))
+	for _, pair := range wordPairs {
+		results = append(results, &models.InlineQueryResultArticle{
+			ID:          strconv.FormatUint(uint64(pair.ID), 10),
+			Title:       pair.Word1,
+			Description: pair.Word2,
+			InputMessageContent: &models.InputTextMessageContent{
+				MessageText: pair.Word1 + " — " + pair.Word2,
+			},
+		})
+	}
+
+	// Results are per user, so they must not be cached for everyone
+	if _, err := b.AnswerInlineQuery(ctx, &bot.AnswerInlineQueryParams{
+		InlineQueryID: query.ID,
+		Results:       results,
+		IsPersonal:    true,
+		CacheTime:     10,
+	}); err != nil {
+		logger.Error("failed to answer inline query", "user_id", query.From.ID, "error", err)
+	}
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/db"
+)
+
+func TestHandleInlineQuery(t *testing.T) {
+	statements := useDryRunDB(t)
+	statements.result = func(s statement) int64 {
+		if pairs, ok := s.Dest.(*[]db.WordPair); ok {
+			*pairs = []db.WordPair{{ID: 3, UserID: 1, Word1: "50% korting", Word2: "50% off"}}
+			return 1
+		}
+		return 0
+	}
+	b, fake := newTestBot(t)
+
+	HandleInlineQuery(context.Background(), b, &models.Update{InlineQuery: &models.InlineQuery{
+		ID:    "q1",
+		From:  &models.User{ID: 1},
+		Query: "50%_",
+	}})
+
+	sql := statements.all()[0].SQL
+	for _, want := range []string{"user_id = 1", "word2 <> ''", `word1 ILIKE '%50\%\_%'`, `word2 ILIKE '%50\%\_%'`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("query %s does not contain %s", sql, want)
+		}
+	}
+
+	if len(fake.calls) != 1 || fake.calls[0].Method != "answerInlineQuery" {
+		t.Fatalf("made calls %+v, want a single answerInlineQuery", fake.calls)
+	}
+	params := fake.calls[0].Params
+	if params["inline_query_id"] != "q1" || params["is_personal"] != "true" {
+		t.Errorf("answered query %q with is_personal %q, want q1 and true", params["inline_query_id"], params["is_personal"])
+	}
+	var results []struct {
+		Type                string `json:"type"`
+		ID                  string `json:"id"`
+		Title               string `json:"title"`
+		InputMessageContent struct {
+			MessageText string `json:"message_text"`
+		} `json:"input_message_content"`
+	}
+	if err := json.Unmarshal([]byte(params["results"]), &results); err != nil {
+		t.Fatalf("failed to decode results %q: %v", params["results"], err)
+	}
+	if len(results) != 1 || results[0].ID != "3" || results[0].Title != "50% korting" || results[0].InputMessageContent.MessageText != "50% korting — 50% off" {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"strconv"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// MaxPairsPerReminder caps the number of pairs sent in a single reminder
+const MaxPairsPerReminder = 10
+
+const pairsPresetCallbackPrefix = "setnum:"
+
+// pairsPresets are the quick choices offered by /setnum
+var pairsPresets = []int{1, 3, 5, 10}
+
+// pairsPresetKeyboard builds an inline keyboard with one button per pairs count preset
+func pairsPresetKeyboard() *models.InlineKeyboardMarkup {
+	row := make([]models.InlineKeyboardButton, 0, len(pairsPresets))
+	for _, n := range pairsPresets {
+		if n > MaxPairsPerReminder {
+			continue
+		}
+		row = append(row, models.InlineKeyboardButton{
+			Text:         strconv.Itoa(n),
+			CallbackData: pairsPresetCallbackPrefix + strconv.Itoa(n),
+		})
+	}
+	return &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{row}}
+}
@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// presetsOf returns the pairs counts of the keyboard buttons, parsed from their callback data
+func presetsOf(t *testing.T) []int {
+	t.Helper()
+	keyboard := pairsPresetKeyboard()
+	if len(keyboard.InlineKeyboard) != 1 {
+		t.Fatalf("keyboard has %d rows, want 1", len(keyboard.InlineKeyboard))
+	}
+	var counts []int
+	for _, button := range keyboard.InlineKeyboard[0] {
+		if len(button.CallbackData) > 64 {
+			t.Errorf("callback data %q exceeds Telegram's 64 bytes", button.CallbackData)
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(button.CallbackData, pairsPresetCallbackPrefix))
+		if err != nil || !strings.HasPrefix(button.CallbackData, pairsPresetCallbackPrefix) {
+			t.Errorf("unparseable callback data %q", button.CallbackData)
+		}
+		if button.Text != strconv.Itoa(n) {
+			t.Errorf("button %q sets %d pairs", button.Text, n)
+		}
+		counts = append(counts, n)
+	}
+	return counts
+}
+
+func TestPairsPresetKeyboard(t *testing.T) {
+	if got, want := presetsOf(t), []int{1, 3, 5, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("presets = %v, want %v", got, want)
+	}
+}
+
+func TestPairsPresetKeyboardDropsPresetsAboveMax(t *testing.T) {
+	previous := pairsPresets
+	t.Cleanup(func() { pairsPresets = previous })
+	pairsPresets = []int{5, MaxPairsPerReminder, MaxPairsPerReminder + 1}
+
+	if got, want := presetsOf(t), []int{5, MaxPairsPerReminder}; !reflect.DeepEqual(got, want) {
+		t.Errorf("presets = %v, want %v", got, want)
+	}
+}
@@ -5,12 +5,61 @@ import (
 	"math/rand"
 
 	"github.com/go-telegram/bot"
+	"github.com/smith3v/tg-word-reminder/pkg/db"
 )
 
-// PrepareWordPairMessage formats a word pair message with a random order of the words, hiding one under a spoiler
-func PrepareWordPairMessage(word1, word2 string) string {
-	if rand.Intn(2) == 0 {
-		return fmt.Sprintf("%s  ||_%s_||\n", bot.EscapeMarkdown(word1), bot.EscapeMarkdown(word2))
+const (
+	// MaxWordLength is the longest word accepted on import, in characters
+	MaxWordLength = 200
+	// maxMessageLength is Telegram's limit for the text of a single message
+	maxMessageLength = 4096
+	// maxLabelLength is the longest column label accepted by /labels, in characters
+	maxLabelLength = 20
+)
+
+// Which word of a pair is hidden under the spoiler
+const (
+	HideRandom = "random"
+	HideFirst  = "first"
+	HideSecond = "second"
+	HideNone   = "none" // Both words shown without a spoiler, e.g. for screen readers
+)
+
+// PrepareWordPairMessage formats a word pair message, hiding one of the words under a spoiler.
+// The user's HiddenWord selects the hidden word; HideNone shows both, and any other value hides one at random.
+// Words are prefixed with the user's labels when set.
+func PrepareWordPairMessage(word1, word2 string, user db.UserSettings) string {
+	word1, word2 = bot.EscapeMarkdown(truncateWord(word1)), bot.EscapeMarkdown(truncateWord(word2))
+	label1, label2 := wordLabel(user.WordLabel1), wordLabel(user.WordLabel2)
+	if user.HiddenWord == HideNone {
+		return fmt.Sprintf("%s%s  %s_%s_\n", label1, word1, label2, word2)
+	}
+	hideFirst := rand.Intn(2) == 1
+	switch user.HiddenWord {
+	case HideFirst:
+		hideFirst = true
+	case HideSecond:
+		hideFirst = false
+	}
+	if !hideFirst {
+		return fmt.Sprintf("%s%s  %s||_%s_||\n", label1, word1, label2, word2)
+	}
+	return fmt.Sprintf("%s_%s_  %s||%s||\n", label2, word2, label1, word1)
+}
+
+// wordLabel formats a column label to prefix a word with, or returns "" when no label is set
+func wordLabel(label string) string {
+	if label == "" {
+		return ""
+	}
+	return bot.EscapeMarkdown(label) + ": "
+}
+
+// truncateWord shortens words longer than MaxWordLength, marking the cut with an ellipsis
+func truncateWord(word string) string {
+	runes := []rune(word)
+	if len(runes) <= MaxWordLength {
+		return word
 	}
-	return fmt.Sprintf("_%s_  ||%s||\n", bot.EscapeMarkdown(word2), bot.EscapeMarkdown(word1))
+	return string(runes[:MaxWordLength-1]) + "…"
 }
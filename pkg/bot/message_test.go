@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/smith3v/tg-word-reminder/pkg/db"
+)
+
+func TestTruncateWord(t *testing.T) {
+	exact := strings.Repeat("é", MaxWordLength)
+	if got := truncateWord(exact); got != exact {
+		t.Errorf("a word of %d characters was changed to %q", MaxWordLength, got)
+	}
+
+	long := strings.Repeat("é", MaxWordLength+1)
+	got := truncateWord(long)
+	if n := utf8.RuneCountInString(got); n != MaxWordLength {
+		t.Errorf("truncated word has %d characters, want %d", n, MaxWordLength)
+	}
+	if !strings.HasSuffix(got, "…") {
+		t.Errorf("truncated word %q does not end with an ellipsis", got)
+	}
+}
+
+func TestReminderMessageFitsTelegramLimit(t *testing.T) {
+	// Every character of these words needs a Markdown escape, doubling their length
+	word := strings.Repeat("*", MaxWordLength)
+	pairs := make([]db.WordPair, MaxPairsPerReminder)
+	for i := range pairs {
+		pairs[i] = db.WordPair{Word1: word, Word2: word}
+	}
+	user := db.UserSettings{WordLabel1: strings.Repeat("_", maxLabelLength), WordLabel2: strings.Repeat("_", maxLabelLength)}
+
+	message := buildReminderMessage(pairs, user)
+	if len(message) > maxMessageLength {
+		t.Errorf("reminder is %d bytes long, more than %d", len(message), maxMessageLength)
+	}
+	if message == "" {
+		t.Error("reminder is empty, want at least one pair")
+	}
+}
+
+func TestPrepareWordPairMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		user db.UserSettings
+		want string
+	}{
+		{
+			name: "hide first",
+			user: db.UserSettings{HiddenWord: HideFirst},
+			want: "_house\\!_  ||huis\\.||\n",
+		},
+		{
+			name: "hide second",
+			user: db.UserSettings{HiddenWord: HideSecond},
+			want: "huis\\.  ||_house\\!_||\n",
+		},
+		{
+			name: "hide none",
+			user: db.UserSettings{HiddenWord: HideNone},
+			want: "huis\\.  _house\\!_\n",
+		},
+		{
+			name: "labels",
+			user: db.UserSettings{HiddenWord: HideSecond, WordLabel1: "NL (nl)", WordLabel2: "EN"},
+			want: "NL \\(nl\\): huis\\.  EN: ||_house\\!_||\n",
+		},
+		{
+			name: "labels with hidden first word",
+			user: db.UserSettings{HiddenWord: HideFirst, WordLabel1: "NL", WordLabel2: "EN"},
+			want: "EN: _house\\!_  NL: ||huis\\.||\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrepareWordPairMessage("huis.", "house!", tt.user); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareWordPairMessageRandomHidesOneWord(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		got := PrepareWordPairMessage("huis", "house", db.UserSettings{HiddenWord: HideRandom})
+		if got != "huis  ||_house_||\n" && got != "_house_  ||huis||\n" {
+			t.Fatalf("unexpected message %q", got)
+		}
+	}
+	if got := PrepareWordPairMessage("huis", "house", db.UserSettings{HiddenWord: HideNone}); strings.Contains(got, "||") {
+		t.Errorf("message %q has a spoiler although hiding is off", got)
+	}
+}
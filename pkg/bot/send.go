@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/logger"
+)
+
+// maxSendRetries is how many times a message is resent after a flood-wait error
+const maxSendRetries = 3
+
+// sendWithRetry sends a message and, when Telegram asks to slow down, waits for the requested
+// retry_after before trying again instead of hammering the API
+func sendWithRetry(ctx context.Context, b *bot.Bot, params *bot.SendMessageParams) (*models.Message, error) {
+	for attempt := 0; ; attempt++ {
+		msg, err := b.SendMessage(ctx, params)
+		var floodErr *bot.TooManyRequestsError
+		if err == nil || !errors.As(err, &floodErr) || attempt >= maxSendRetries {
+			return msg, err
+		}
+
+		wait := time.Duration(floodErr.RetryAfter) * time.Second
+		logger.Info("flood wait from Telegram, delaying send", "chat_id", params.ChatID, "retry_after", wait, "attempt", attempt+1)
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done. Tests replace it to skip the actual flood waits.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// replyParams returns the parameters of a reply to msg, sent to the same chat and forum topic
+func replyParams(msg *models.Message, text string) *bot.SendMessageParams {
+	return &bot.SendMessageParams{
+		ChatID:          msg.Chat.ID,
+		MessageThreadID: msg.MessageThreadID,
+		Text:            text,
+	}
+}
+
+// reply answers msg with a plain text message in the same chat and forum topic
+func reply(ctx context.Context, b *bot.Bot, msg *models.Message, text string) (*models.Message, error) {
+	return b.SendMessage(ctx, replyParams(msg, text))
+}
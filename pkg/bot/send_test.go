@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// fakeSleep replaces sleep for the duration of the test and returns the waits requested
+func fakeSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	previous := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { sleep = previous })
+	return &waits
+}
+
+const floodWaitResponse = `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`
+
+func TestSendWithRetryWaitsOutFloodWait(t *testing.T) {
+	waits := fakeSleep(t)
+	b, fake := newTestBot(t)
+	fake.respond = func(method string, n int) string {
+		if n == 1 {
+			return floodWaitResponse
+		}
+		return ""
+	}
+
+	msg, err := sendWithRetry(context.Background(), b, &bot.SendMessageParams{ChatID: 1, Text: "huis"})
+	if err != nil {
+		t.Fatalf("sendWithRetry: %v", err)
+	}
+	if msg == nil {
+		t.Fatal("no message returned after the retry")
+	}
+	if len(fake.sent()) != 2 {
+		t.Errorf("sent %d times, want 2", len(fake.sent()))
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Errorf("waited %v, want a single 7s wait", *waits)
+	}
+}
+
+func TestSendWithRetryGivesUp(t *testing.T) {
+	waits := fakeSleep(t)
+	b, fake := newTestBot(t)
+	fake.respond = func(method string, n int) string { return floodWaitResponse }
+
+	_, err := sendWithRetry(context.Background(), b, &bot.SendMessageParams{ChatID: 1, Text: "huis"})
+	var floodErr *bot.TooManyRequestsError
+	if !errors.As(err, &floodErr) {
+		t.Fatalf("got error %v, want a flood-wait error", err)
+	}
+	if len(fake.sent()) != maxSendRetries+1 || len(*waits) != maxSendRetries {
+		t.Errorf("sent %d times with %d waits, want %d and %d", len(fake.sent()), len(*waits), maxSendRetries+1, maxSendRetries)
+	}
+}
+
+func TestReplyKeepsForumTopic(t *testing.T) {
+	b, fake := newTestBot(t)
+	msg := testMessage("/help")
+	msg.Chat = models.Chat{ID: -100123, Type: models.ChatTypeSupergroup}
+	msg.MessageThreadID = 7
+
+	HandleHelp(context.Background(), b, &models.Update{Message: msg})
+	if _, err := reply(context.Background(), b, msg, "huis"); err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+
+	if len(fake.calls) != 2 {
+		t.Fatalf("made %d calls, want 2", len(fake.calls))
+	}
+	for _, call := range fake.calls {
+		if call.Params["chat_id"] != "-100123" || call.Params["message_thread_id"] != "7" {
+			t.Errorf("%s sent to chat %q topic %q, want chat -100123 topic 7", call.Method, call.Params["chat_id"], call.Params["message_thread_id"])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"strings"
+	"unicode"
+)
+
+// hasNearDuplicate reports whether word differs from any of the existing words by exactly one edit, ignoring case
+func hasNearDuplicate(word string, existing []string) bool {
+	w := []rune(strings.ToLower(word))
+	for _, e := range existing {
+		r := []rune(strings.ToLower(e))
+		if d := len(w) - len(r); d > 1 || d < -1 {
+			continue // Cheap length check before computing the distance
+		}
+		if levenshtein(w, r) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// scripts are the writing systems recognized by dominantScript
+var scripts = map[string]*unicode.RangeTable{
+	"Latin":      unicode.Latin,
+	"Cyrillic":   unicode.Cyrillic,
+	"Greek":      unicode.Greek,
+	"Arabic":     unicode.Arabic,
+	"Hebrew":     unicode.Hebrew,
+	"Han":        unicode.Han,
+	"Hiragana":   unicode.Hiragana,
+	"Katakana":   unicode.Katakana,
+	"Hangul":     unicode.Hangul,
+	"Thai":       unicode.Thai,
+	"Devanagari": unicode.Devanagari,
+	"Georgian":   unicode.Georgian,
+	"Armenian":   unicode.Armenian,
+}
+
+// dominantScript returns the name of the script most letters of s are written in, or "" if it has no known letters
+func dominantScript(s string) string {
+	counts := make(map[string]int)
+	for _, r := range s {
+		for name, table := range scripts {
+			if unicode.Is(table, r) {
+				counts[name]++
+				break
+			}
+		}
+	}
+	best, bestCount := "", 0
+	for name, count := range counts {
+		if count > bestCount {
+			best, bestCount = name, count
+		}
+	}
+	return best
+}
+
+// looksSameLanguage reports whether both words of a pair seem to be in the same language:
+// either they are identical, or both are written in the same non-Latin script.
+// Latin is shared by too many languages to tell them apart this way.
+func looksSameLanguage(word1, word2 string) bool {
+	if strings.EqualFold(word1, word2) {
+		return true
+	}
+	script := dominantScript(word1)
+	return script != "" && script != "Latin" && script == dominantScript(word2)
+}
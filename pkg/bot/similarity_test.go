@@ -0,0 +1,60 @@
+package bot
+
+import "testing"
+
+func TestHasNearDuplicate(t *testing.T) {
+	existing := []string{"colour", "house", "kat"}
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"color", true},
+		{"Colour", false}, // Same word in another case is not a misspelling
+		{"hous", true},
+		{"dog", false},
+		{"garden", false},
+		{"katten", false},
+	}
+	for _, tt := range tests {
+		if got := hasNearDuplicate(tt.word, existing); got != tt.want {
+			t.Errorf("hasNearDuplicate(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"color", "colour", 1},
+		{"über", "uber", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLooksSameLanguage(t *testing.T) {
+	tests := []struct {
+		word1, word2 string
+		want         bool
+	}{
+		{"дом", "house", false},
+		{"huis", "house", false}, // Both Latin, which cannot be told apart
+		{"猫", "cat", false},
+		{"дом", "здание", true},
+		{"σπίτι", "οίκος", true},
+		{"House", "house", true},
+	}
+	for _, tt := range tests {
+		if got := looksSameLanguage(tt.word1, tt.word2); got != tt.want {
+			t.Errorf("looksSameLanguage(%q, %q) = %v, want %v", tt.word1, tt.word2, got, tt.want)
+		}
+	}
+}
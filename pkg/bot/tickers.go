@@ -2,17 +2,23 @@ package bot
 
 import (
 	"context"
+	"fmt"
+	"runtime/debug"
 	"time"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/smith3v/tg-word-reminder/pkg/config"
 	"github.com/smith3v/tg-word-reminder/pkg/db"
 	"github.com/smith3v/tg-word-reminder/pkg/logger"
 )
 
+// errorLogWindow is how often a failure repeating for the same user is logged, e.g. when the user blocked the bot
+const errorLogWindow = time.Hour
+
 func StartPeriodicMessages(ctx context.Context, b *bot.Bot) {
 	var users []db.UserSettings
-	if err := db.DB.Find(&users).Error; err != nil {
+	if err := db.DB.Where("bot_id = ?", config.AppConfig.Telegram.BotID).Find(&users).Error; err != nil {
 		logger.Error("failed to fetch users for reminders", "error", err)
 		return
 	}
@@ -24,13 +30,23 @@ func StartPeriodicMessages(ctx context.Context, b *bot.Bot) {
 
 	// Initialize tickers for existing users
 	for _, user := range users {
-		tickers = append(tickers, createUserTicker(user)) // Create ticker for each user
+		if t, ok := createUserTicker(user); ok {
+			tickers = append(tickers, t) // Create ticker for each user
+		}
 	}
 
-	// Ticker for checking user settings and new users every 5 minutes
-	settingsUpdateTicker := time.NewTicker(5 * time.Minute)
+	intervals := config.AppConfig.Reminders
+
+	// Ticker for checking user settings and new users
+	settingsUpdateTicker := time.NewTicker(intervals.SettingsRefreshInterval())
 	defer settingsUpdateTicker.Stop()
 
+	// Ticker for checking whose word of the day is due
+	wordOfTheDayTicker := time.NewTicker(intervals.WordOfTheDayScanInterval())
+	defer wordOfTheDayTicker.Stop()
+
+	pollInterval := intervals.PollInterval()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -40,12 +56,17 @@ func StartPeriodicMessages(ctx context.Context, b *bot.Bot) {
 			return
 		case <-settingsUpdateTicker.C:
 			updateUserTickers(&tickers) // Check for user settings updates and new users
+		case <-wordOfTheDayTicker.C:
+			sendWordsOfTheDay(ctx, b)
 		default:
-			time.Sleep(1000 * time.Millisecond) // Adjust the duration as needed
+			time.Sleep(pollInterval)
 			for _, t := range tickers {
 				select {
 				case <-t.ticker.C:
-					sendReminders(ctx, b, t.user) // Send reminders for the corresponding user
+					user := t.user
+					runForUser("reminder", user.UserID, func() {
+						sendReminders(ctx, b, user, time.Now()) // Send reminders for the corresponding user
+					})
 				default:
 					continue
 				}
@@ -54,22 +75,34 @@ func StartPeriodicMessages(ctx context.Context, b *bot.Bot) {
 	}
 }
 
-// Helper function to create a ticker for a user
-func createUserTicker(user db.UserSettings) struct {
+// Helper function to create a ticker for a user.
+// Users with an out-of-range reminders count get no ticker, since time.NewTicker panics on a non-positive interval.
+func createUserTicker(user db.UserSettings) (struct {
 	ticker *time.Ticker
 	user   db.UserSettings
-} {
-	var ticker *time.Ticker
-	if user.RemindersPerDay > 24 {
-		interval := time.Duration(24*60/user.RemindersPerDay) * time.Minute
-		ticker = time.NewTicker(interval)
-	} else {
-		ticker = time.NewTicker(time.Duration(24 * int(time.Hour) / user.RemindersPerDay))
-	}
-	return struct {
+}, bool) {
+	var t struct {
 		ticker *time.Ticker
 		user   db.UserSettings
-	}{ticker: ticker, user: user}
+	}
+	interval := reminderInterval(user.RemindersPerDay)
+	if interval <= 0 {
+		logger.ErrorLimited(fmt.Sprintf("ticker:%d", user.UserID), errorLogWindow, "invalid reminders per day, not scheduling reminders", "user_id", user.UserID, "reminders_per_day", user.RemindersPerDay)
+		return t, false
+	}
+	t.ticker, t.user = time.NewTicker(interval), user
+	return t, true
+}
+
+// reminderInterval returns the time between two reminders, or zero when remindersPerDay is out of range
+func reminderInterval(remindersPerDay int) time.Duration {
+	if remindersPerDay <= 0 || remindersPerDay > maxRemindersPerDay {
+		return 0
+	}
+	if remindersPerDay > 24 {
+		return time.Duration(24*60/remindersPerDay) * time.Minute
+	}
+	return 24 * time.Hour / time.Duration(remindersPerDay)
 }
 
 // Function to update user tickers based on settings changes and check for new users
@@ -78,7 +111,7 @@ func updateUserTickers(tickers *[]struct {
 	user   db.UserSettings
 }) {
 	var users []db.UserSettings
-	if err := db.DB.Find(&users).Error; err != nil {
+	if err := db.DB.Where("bot_id = ?", config.AppConfig.Telegram.BotID).Find(&users).Error; err != nil {
 		logger.Error("failed to fetch users for settings update", "error", err)
 		return
 	}
@@ -90,16 +123,24 @@ func updateUserTickers(tickers *[]struct {
 
 	for _, user := range users {
 		if _, exists := existingUserIDs[user.UserID]; !exists {
-			logger.Debug("new user detected", "user_id", user.UserID)
-			*tickers = append(*tickers, createUserTicker(user)) // Create ticker for new user
+			if t, ok := createUserTicker(user); ok {
+				logger.Debug("new user detected", "user_id", user.UserID)
+				*tickers = append(*tickers, t) // Create ticker for new user
+			}
 		} else {
 			// Check if the settings have changed
 			for i, t := range *tickers {
 				if t.user.UserID == user.UserID {
-					if t.user.RemindersPerDay != user.RemindersPerDay || t.user.PairsToSend != user.PairsToSend {
+					if t.user.RemindersPerDay != user.RemindersPerDay {
 						logger.Debug("user settings updated", "user_id", user.UserID, "old_settings", t.user, "new_settings", user)
-						t.ticker.Stop()                        // Stop the old ticker
-						(*tickers)[i] = createUserTicker(user) // Recreate the ticker with updated settings
+						t.ticker.Stop() // Stop the old ticker
+						if updated, ok := createUserTicker(user); ok {
+							(*tickers)[i] = updated // Recreate the ticker with updated settings
+						} else {
+							*tickers = append((*tickers)[:i], (*tickers)[i+1:]...) // Drop the user until the setting is fixed
+						}
+					} else {
+						(*tickers)[i].user = user // Keep the schedule, only refresh the settings
 					}
 					break
 				}
@@ -108,25 +149,145 @@ func updateUserTickers(tickers *[]struct {
 	}
 }
 
-func sendReminders(ctx context.Context, b *bot.Bot, user db.UserSettings) {
-	var wordPairs []db.WordPair
-	if err := db.DB.Where("user_id = ?", user.UserID).Order("RANDOM()").Limit(user.PairsToSend).Find(&wordPairs).Error; err != nil {
+func sendReminders(ctx context.Context, b *bot.Bot, user db.UserSettings, now time.Time) {
+	if onVacation(user, now) {
+		logger.Debug("skipping reminder during vacation", "user_id", user.UserID, "resume_at", *user.ResumeAt)
+		return
+	}
+	if user.ResumeAt != nil {
+		// The vacation is over, so clear it and carry on with the reminder
+		if err := settingsQuery(user.UserID).Model(&db.UserSettings{}).Update("resume_at", nil).Error; err != nil {
+			logger.Error("failed to clear vacation", "user_id", user.UserID, "error", err)
+		}
+	}
+
+	if user.WeekdaysOnly && isWeekend(now) {
+		logger.Debug("skipping weekend reminder", "user_id", user.UserID)
+		return
+	}
+
+	wordPairs, err := selectRandomPairs(user.UserID, user.PairsToSend)
+	if err != nil {
 		logger.Error("failed to fetch word pairs for user", "user_id", user.UserID, "error", err)
 		return
 	}
 
 	if len(wordPairs) > 0 {
-		message := ""
-		for _, pair := range wordPairs {
-			message += PrepareWordPairMessage(pair.Word1, pair.Word2)
-		}
-		_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		_, err := sendWithRetry(ctx, b, &bot.SendMessageParams{
 			ChatID:    user.UserID,
-			Text:      message,
+			Text:      buildReminderMessage(wordPairs, user),
 			ParseMode: models.ParseModeMarkdown,
 		})
 		if err != nil {
-			logger.Error("failed to send reminder message", "user_id", user.UserID, "error", err)
+			logger.ErrorLimited(fmt.Sprintf("reminder:%d", user.UserID), errorLogWindow, "failed to send reminder message", "user_id", user.UserID, "error", err)
+			return
+		}
+		if err := settingsQuery(user.UserID).Model(&db.UserSettings{}).Update("last_reminder_at", time.Now()).Error; err != nil {
+			logger.Error("failed to store reminder time", "user_id", user.UserID, "error", err)
+		}
+	}
+}
+
+// buildReminderMessage formats the pairs of a reminder, dropping the pairs that would not fit into one message
+func buildReminderMessage(wordPairs []db.WordPair, user db.UserSettings) string {
+	message := ""
+	for _, pair := range wordPairs {
+		line := PrepareWordPairMessage(pair.Word1, pair.Word2, user)
+		if len(message)+len(line) > maxMessageLength {
+			logger.Debug("reminder message is full, dropping remaining pairs", "user_id", user.UserID)
+			break
+		}
+		message += line
+	}
+	return message
+}
+
+// sendWordsOfTheDay sends one random pair to every opted-in user whose word of the day is due
+func sendWordsOfTheDay(ctx context.Context, b *bot.Bot) {
+	now := time.Now()
+	if now.Hour() < config.AppConfig.Reminders.WordOfTheDayHour {
+		return
+	}
+
+	var users []db.UserSettings
+	if err := db.DB.Where("bot_id = ? AND word_of_the_day = ?", config.AppConfig.Telegram.BotID, true).Find(&users).Error; err != nil {
+		logger.Error("failed to fetch users for word of the day", "error", err)
+		return
+	}
+
+	for _, user := range users {
+		if !wordOfTheDayDue(user, now) {
+			continue
 		}
+		runForUser("word of the day", user.UserID, func() {
+			sendWordOfTheDay(ctx, b, user, now)
+		})
+	}
+}
+
+func sendWordOfTheDay(ctx context.Context, b *bot.Bot, user db.UserSettings, now time.Time) {
+	wordPairs, err := selectRandomPairs(user.UserID, 1)
+	if err != nil {
+		logger.Error("failed to fetch word of the day", "user_id", user.UserID, "error", err)
+		return
+	}
+	if len(wordPairs) == 0 {
+		return
+	}
+
+	_, err = sendWithRetry(ctx, b, &bot.SendMessageParams{
+		ChatID:    user.UserID,
+		Text:      "*Word of the day*\n\n" + PrepareWordPairMessage(wordPairs[0].Word1, wordPairs[0].Word2, user),
+		ParseMode: models.ParseModeMarkdown,
+	})
+	if err != nil {
+		logger.ErrorLimited(fmt.Sprintf("wotd:%d", user.UserID), errorLogWindow, "failed to send word of the day", "user_id", user.UserID, "error", err)
+		return
 	}
+
+	if err := settingsQuery(user.UserID).Model(&db.UserSettings{}).Update("last_wotd_at", now).Error; err != nil {
+		logger.Error("failed to store word of the day time", "user_id", user.UserID, "error", err)
+	}
+}
+
+// runForUser runs one user's periodic task, recovering from a panic so that a single bad row
+// cannot stop the reminder loop for everyone
+func runForUser(task string, userID int64, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("recovered from panic in periodic task", "task", task, "user_id", userID, "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+	fn()
+}
+
+// onVacation reports whether the user's reminders are paused at now; they resume exactly at ResumeAt
+func onVacation(user db.UserSettings, now time.Time) bool {
+	return user.ResumeAt != nil && now.Before(*user.ResumeAt)
+}
+
+// wordOfTheDayDue reports whether the user should get a word of the day at now
+func wordOfTheDayDue(user db.UserSettings, now time.Time) bool {
+	if !user.WordOfTheDay || onVacation(user, now) {
+		return false
+	}
+	if user.LastWotdAt == nil {
+		return true
+	}
+	last := user.LastWotdAt.In(now.Location())
+	return last.YearDay() != now.YearDay() || last.Year() != now.Year()
+}
+
+// selectRandomPairs returns up to limit random pairs of the user, leaving out pending words without a translation
+func selectRandomPairs(userID int64, limit int) ([]db.WordPair, error) {
+	var wordPairs []db.WordPair
+	err := db.DB.Where("user_id = ? AND word2 <> ''", userID).Order("RANDOM()").Limit(limit).Find(&wordPairs).Error
+	return wordPairs, err
+}
+
+// isWeekend reports whether t falls on a Saturday or Sunday in the location of t.
+// The reminder loop passes the server's local time, as users have no timezone setting.
+func isWeekend(t time.Time) bool {
+	day := t.Weekday()
+	return day == time.Saturday || day == time.Sunday
 }
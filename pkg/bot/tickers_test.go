@@ -0,0 +1,202 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith3v/tg-word-reminder/pkg/config"
+	"github.com/smith3v/tg-word-reminder/pkg/db"
+)
+
+func TestReminderInterval(t *testing.T) {
+	tests := []struct {
+		remindersPerDay int
+		want            time.Duration
+	}{
+		{1, 24 * time.Hour},
+		{4, 6 * time.Hour},
+		{24, time.Hour},
+		{48, 30 * time.Minute},
+		{maxRemindersPerDay, time.Minute},
+		{maxRemindersPerDay + 1, 0},
+		{0, 0},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		if got := reminderInterval(tt.remindersPerDay); got != tt.want {
+			t.Errorf("reminderInterval(%d) = %v, want %v", tt.remindersPerDay, got, tt.want)
+		}
+	}
+}
+
+func TestRemindersAreScopedToBot(t *testing.T) {
+	statements := useDryRunDB(t)
+	previous := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = previous })
+	config.AppConfig.Telegram.BotID = "bot-a"
+	config.AppConfig.Reminders.WordOfTheDayHour = 0
+
+	var settings db.UserSettings
+	settingsQuery(1).First(&settings)
+	var tickers []struct {
+		ticker *time.Ticker
+		user   db.UserSettings
+	}
+	updateUserTickers(&tickers)
+	sendWordsOfTheDay(context.Background(), nil)
+
+	all := statements.all()
+	if len(all) != 3 {
+		t.Fatalf("built %d statements, want 3", len(all))
+	}
+	for _, s := range all {
+		if !strings.Contains(s.SQL, "bot_id = 'bot-a'") {
+			t.Errorf("statement is not limited to this bot's users: %s", s.SQL)
+		}
+	}
+}
+
+func TestRunForUserSurvivesPanic(t *testing.T) {
+	logs := captureLog(t)
+
+	var processed []int64
+	for _, userID := range []int64{1, 2} {
+		runForUser("reminder", userID, func() {
+			if userID == 1 {
+				panic("bad settings row")
+			}
+			processed = append(processed, userID)
+		})
+	}
+
+	if len(processed) != 1 || processed[0] != 2 {
+		t.Errorf("processed users %v after a panic, want [2]", processed)
+	}
+	if line := logs.String(); !strings.Contains(line, "user_id=1") || !strings.Contains(line, `panic="bad settings row"`) {
+		t.Errorf("panic not logged for user 1: %s", line)
+	}
+}
+
+func TestOnVacation(t *testing.T) {
+	resumeAt := time.Date(2024, 7, 15, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		resumeAt *time.Time
+		now      time.Time
+		want     bool
+	}{
+		{"no vacation", nil, resumeAt, false},
+		{"just before resuming", &resumeAt, resumeAt.Add(-time.Second), true},
+		{"exactly at resuming", &resumeAt, resumeAt, false},
+		{"after resuming", &resumeAt, resumeAt.Add(time.Minute), false},
+	}
+	for _, tt := range tests {
+		if got := onVacation(db.UserSettings{ResumeAt: tt.resumeAt}, tt.now); got != tt.want {
+			t.Errorf("%s: onVacation = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSendRemindersSkipsWeekend(t *testing.T) {
+	saturday := time.Date(2024, 7, 13, 10, 0, 0, 0, time.UTC)
+	monday := time.Date(2024, 7, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		weekdaysOnly bool
+		now          time.Time
+		wantSent     bool
+	}{
+		{"weekdays only on Saturday", true, saturday, false},
+		{"weekdays only on Sunday", true, saturday.AddDate(0, 0, 1), false},
+		{"weekdays only on Monday", true, monday, true},
+		{"every day on Saturday", false, saturday, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements := useDryRunDB(t)
+			statements.result = func(s statement) int64 {
+				if pairs, ok := s.Dest.(*[]db.WordPair); ok {
+					*pairs = []db.WordPair{{UserID: 1, Word1: "huis", Word2: "house"}}
+					return 1
+				}
+				return 0
+			}
+			b, fake := newTestBot(t)
+
+			user := db.UserSettings{UserID: 1, PairsToSend: 1, RemindersPerDay: 1, WeekdaysOnly: tt.weekdaysOnly}
+			sendReminders(context.Background(), b, user, tt.now)
+
+			if sent := len(fake.sent()) > 0; sent != tt.wantSent {
+				t.Errorf("reminder sent = %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestWordOfTheDayDue(t *testing.T) {
+	now := time.Date(2024, 7, 15, 9, 30, 0, 0, time.UTC)
+	at := func(t time.Time) *time.Time { return &t }
+	tests := []struct {
+		name string
+		user db.UserSettings
+		want bool
+	}{
+		{"turned off", db.UserSettings{}, false},
+		{"never sent", db.UserSettings{WordOfTheDay: true}, true},
+		{"sent earlier today", db.UserSettings{WordOfTheDay: true, LastWotdAt: at(now.Add(-time.Hour))}, false},
+		{"sent yesterday", db.UserSettings{WordOfTheDay: true, LastWotdAt: at(now.AddDate(0, 0, -1))}, true},
+		{"sent on the same day a year ago", db.UserSettings{WordOfTheDay: true, LastWotdAt: at(now.AddDate(-1, 0, 0))}, true},
+		{"on vacation", db.UserSettings{WordOfTheDay: true, ResumeAt: at(now.Add(time.Hour))}, false},
+	}
+	for _, tt := range tests {
+		if got := wordOfTheDayDue(tt.user, now); got != tt.want {
+			t.Errorf("%s: wordOfTheDayDue = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSendWordsOfTheDayOncePerDay(t *testing.T) {
+	statements := useDryRunDB(t)
+	previous := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = previous })
+	config.AppConfig.Reminders.WordOfTheDayHour = 0
+
+	user := db.UserSettings{UserID: 1, WordOfTheDay: true}
+	statements.result = func(s statement) int64 {
+		switch dest := s.Dest.(type) {
+		case *[]db.UserSettings:
+			*dest = []db.UserSettings{user}
+			return 1
+		case *[]db.WordPair:
+			*dest = []db.WordPair{{UserID: 1, Word1: "huis", Word2: "house"}}
+			return 1
+		}
+		return 0
+	}
+	b, fake := newTestBot(t)
+
+	sendWordsOfTheDay(context.Background(), b)
+	if len(fake.sent()) != 1 {
+		t.Fatalf("sent %d words of the day, want 1", len(fake.sent()))
+	}
+	if sql := statements.all()[0].SQL; !strings.Contains(sql, "word_of_the_day = true") {
+		t.Errorf("users are not filtered on the word of the day setting: %s", sql)
+	}
+	var stored bool
+	for _, s := range statements.all() {
+		stored = stored || (strings.HasPrefix(s.SQL, "UPDATE") && strings.Contains(s.SQL, "last_wotd_at"))
+	}
+	if !stored {
+		t.Error("the time of the word of the day was not stored")
+	}
+
+	// The stored time keeps the next scan on the same day from sending another one
+	now := time.Now()
+	user.LastWotdAt = &now
+	sendWordsOfTheDay(context.Background(), b)
+	if len(fake.sent()) != 1 {
+		t.Errorf("sent %d words of the day after a second scan, want 1", len(fake.sent()))
+	}
+}
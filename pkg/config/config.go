@@ -2,14 +2,17 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
+	"time"
 
 	"github.com/smith3v/tg-word-reminder/pkg/logger"
 )
 
 type Config struct {
-	Database DatabaseConfig `json:"database"`
-	Telegram TelegramConfig `json:"telegram"`
+	Database  DatabaseConfig  `json:"database"`
+	Telegram  TelegramConfig  `json:"telegram"`
+	Reminders RemindersConfig `json:"reminders"`
 }
 
 type DatabaseConfig struct {
@@ -23,6 +26,29 @@ type DatabaseConfig struct {
 
 type TelegramConfig struct {
 	Token string `json:"token"`
+	BotID string `json:"bot_id"` // Separates the users of bot instances sharing one database; empty for a single bot
+}
+
+type RemindersConfig struct {
+	WordOfTheDayHour        int `json:"word_of_the_day_hour"`         // Local server hour after which the word of the day is sent
+	PollIntervalSeconds     int `json:"poll_interval_seconds"`        // How often the user tickers are checked
+	SettingsRefreshMinutes  int `json:"settings_refresh_minutes"`     // How often settings changes and new users are picked up
+	WordOfTheDayScanMinutes int `json:"word_of_the_day_scan_minutes"` // How often users are checked for a due word of the day
+}
+
+// PollInterval is how often the reminder loop checks the user tickers
+func (c RemindersConfig) PollInterval() time.Duration {
+	return time.Duration(c.PollIntervalSeconds) * time.Second
+}
+
+// SettingsRefreshInterval is how often the reminder loop picks up settings changes and new users
+func (c RemindersConfig) SettingsRefreshInterval() time.Duration {
+	return time.Duration(c.SettingsRefreshMinutes) * time.Minute
+}
+
+// WordOfTheDayScanInterval is how often the reminder loop looks for a due word of the day
+func (c RemindersConfig) WordOfTheDayScanInterval() time.Duration {
+	return time.Duration(c.WordOfTheDayScanMinutes) * time.Minute
 }
 
 var AppConfig Config
@@ -35,11 +61,28 @@ func LoadConfig(filename string) error {
 	}
 	defer file.Close()
 
+	// Defaults for optional settings, overridden by the file when present
+	AppConfig.Reminders.WordOfTheDayHour = 9
+	AppConfig.Reminders.PollIntervalSeconds = 1
+	AppConfig.Reminders.SettingsRefreshMinutes = 5
+	AppConfig.Reminders.WordOfTheDayScanMinutes = 1
+
 	decoder := json.NewDecoder(file)
 	if err := decoder.Decode(&AppConfig); err != nil {
 		logger.Error("failed to decode config file", "error", err)
 		return err
 	}
 
+	if AppConfig.Reminders.PollIntervalSeconds <= 0 || AppConfig.Reminders.SettingsRefreshMinutes <= 0 || AppConfig.Reminders.WordOfTheDayScanMinutes <= 0 {
+		err := errors.New("reminder intervals must be positive")
+		logger.Error("invalid config", "error", err)
+		return err
+	}
+	if AppConfig.Reminders.WordOfTheDayHour < 0 || AppConfig.Reminders.WordOfTheDayHour > 23 {
+		err := errors.New("word_of_the_day_hour must be between 0 and 23")
+		logger.Error("invalid config", "error", err)
+		return err
+	}
+
 	return nil
 }
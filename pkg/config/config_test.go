@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// loadFrom writes content to a temporary config file and loads it
+func loadFrom(t *testing.T, content string) error {
+	t.Helper()
+	previous := AppConfig
+	t.Cleanup(func() { AppConfig = previous })
+	AppConfig = Config{}
+
+	filename := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return LoadConfig(filename)
+}
+
+func TestLoadConfigWordOfTheDayHour(t *testing.T) {
+	tests := []struct {
+		hour    string
+		wantErr bool
+	}{
+		{"0", false},
+		{"23", false},
+		{"24", true},
+		{"-1", true},
+	}
+	for _, tt := range tests {
+		err := loadFrom(t, `{"reminders": {"word_of_the_day_hour": `+tt.hour+`}}`)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("word_of_the_day_hour %s: got error %v, want error %v", tt.hour, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadConfigReminderIntervals(t *testing.T) {
+	if err := loadFrom(t, `{"telegram": {"token": "t"}}`); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	defaults := AppConfig.Reminders
+	if defaults.WordOfTheDayHour != 9 || defaults.PollInterval() != time.Second || defaults.SettingsRefreshInterval() != 5*time.Minute || defaults.WordOfTheDayScanInterval() != time.Minute {
+		t.Errorf("unexpected defaults %+v", defaults)
+	}
+
+	if err := loadFrom(t, `{"reminders": {"poll_interval_seconds": 3, "settings_refresh_minutes": 15}}`); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	overridden := AppConfig.Reminders
+	if overridden.PollInterval() != 3*time.Second || overridden.SettingsRefreshInterval() != 15*time.Minute || overridden.WordOfTheDayScanInterval() != time.Minute {
+		t.Errorf("overrides not applied on top of the defaults: %+v", overridden)
+	}
+
+	for _, field := range []string{"poll_interval_seconds", "settings_refresh_minutes", "word_of_the_day_scan_minutes"} {
+		if err := loadFrom(t, `{"reminders": {"`+field+`": 0}}`); err == nil {
+			t.Errorf("a zero %s was accepted", field)
+		}
+	}
+}
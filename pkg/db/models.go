@@ -1,6 +1,8 @@
 // pkg/db/models.go
 package db
 
+import "time"
+
 type WordPair struct {
 	ID     uint   `gorm:"primaryKey"`
 	UserID int64  `gorm:"index"` // To keep pairs separate for each user
@@ -9,8 +11,18 @@ type WordPair struct {
 }
 
 type UserSettings struct {
-	ID              uint  `gorm:"primaryKey"`
-	UserID          int64 `gorm:"index"`
-	PairsToSend     int   `gorm:"default:1"` // Default to sending 1 pair
-	RemindersPerDay int   `gorm:"default:1"` // Default to 1 reminder per day
+	ID              uint       `gorm:"primaryKey"`
+	UserID          int64      `gorm:"index"`
+	BotID           string     `gorm:"index;default:''"` // Bot instance the settings belong to, see TelegramConfig.BotID
+	PairsToSend     int        `gorm:"default:1"`        // Default to sending 1 pair
+	RemindersPerDay int        `gorm:"default:1"`        // Default to 1 reminder per day
+	WeekdaysOnly    bool       `gorm:"default:false"`    // Skip reminders on Saturdays and Sundays
+	ResumeAt        *time.Time // Reminders are paused until this moment when set
+	WordOfTheDay    bool       `gorm:"default:false"` // Send one extra pair every day
+	LastWotdAt      *time.Time // When the last word of the day was sent
+	LastReminderAt  *time.Time // When the last regular reminder was sent
+	HiddenWord      string     `gorm:"default:random"` // Which word of a pair is hidden: first, second or random
+	ImportSeparator string     `gorm:"default:auto"`   // Column separator of uploaded files: auto, tab, comma or semicolon
+	WordLabel1      string     // Label shown before the first word of a pair, e.g. a language name
+	WordLabel2      string     // Label shown before the second word of a pair
 }
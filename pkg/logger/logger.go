@@ -3,6 +3,8 @@ package logger
 import (
 	"log/slog"
 	"os"
+	"sync"
+	"time"
 )
 
 type LogLevel int
@@ -16,8 +18,19 @@ const (
 var (
 	Logger       *slog.Logger
 	currentLevel LogLevel = INFO // Default logging level
+
+	limitedMu     sync.Mutex
+	limitedErrors = make(map[string]*limitedError)
+
+	timeNow = time.Now // Replaced in tests to step through ErrorLimited windows
 )
 
+// limitedError tracks the errors logged under one key by ErrorLimited
+type limitedError struct {
+	loggedAt   time.Time
+	suppressed int
+}
+
 func init() {
 	Logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
 }
@@ -43,3 +56,28 @@ func Error(msg string, args ...any) {
 		Logger.Error(msg, args...)
 	}
 }
+
+// ErrorLimited logs an error like Error, but at most once per window for the same key.
+// Errors suppressed in between are counted and reported with the next entry that gets logged.
+func ErrorLimited(key string, window time.Duration, msg string, args ...any) {
+	limitedMu.Lock()
+	entry, ok := limitedErrors[key]
+	if !ok {
+		entry = &limitedError{}
+		limitedErrors[key] = entry
+	}
+	now := timeNow()
+	if ok && now.Sub(entry.loggedAt) < window {
+		entry.suppressed++
+		limitedMu.Unlock()
+		return
+	}
+	suppressed := entry.suppressed
+	entry.loggedAt, entry.suppressed = now, 0
+	limitedMu.Unlock()
+
+	if suppressed > 0 {
+		args = append(args, "suppressed", suppressed)
+	}
+	Error(msg, args...)
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorLimitedCoalescesRepeatedErrors(t *testing.T) {
+	var buf bytes.Buffer
+	previousLogger, previousNow := Logger, timeNow
+	t.Cleanup(func() { Logger, timeNow = previousLogger, previousNow })
+	Logger = slog.New(slog.NewTextHandler(&buf, nil))
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	const key, window = "test:coalesce", time.Hour
+	for i := 0; i < 4; i++ {
+		ErrorLimited(key, window, "send failed", "user_id", 1)
+		now = now.Add(time.Minute)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("logged %d entries within the window, want 1:\n%s", len(lines), buf.String())
+	}
+	if strings.Contains(lines[0], "suppressed") {
+		t.Errorf("first entry reports suppressed errors: %s", lines[0])
+	}
+
+	now = now.Add(window)
+	ErrorLimited(key, window, "send failed", "user_id", 1)
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logged %d entries after the window, want 2:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[1], "suppressed=3") {
+		t.Errorf("entry after the window does not report 3 suppressed errors: %s", lines[1])
+	}
+}